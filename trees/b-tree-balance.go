@@ -0,0 +1,188 @@
+package trees
+
+/* The balancing of a B-tree is written once, here, and shared by every tree built on it: BTree runs it
+at the order chosen at construction on slice-backed nodes, and the two-three tree runs it at order 3
+on TwoThreeNode. A node type takes part through a nodeLayout, which reads and writes its keys and
+children as slices, so the splits, borrows and merges below never depend on how a node stores them.
+*/
+
+// nodeLayout describes how a node type of a B-tree stores its keys and children.
+// K is the type of the keys as the node holds them, and N the type of a reference to a node.
+type nodeLayout[K any, N comparable] interface {
+	// keys returns the node's keys in ascending order. The slice may be modified and passed to setKeys.
+	keys(node N) []K
+
+	// setKeys replaces the node's keys.
+	setKeys(node N, keys []K)
+
+	// children returns the node's children in order, or none for a leaf.
+	// The slice may be modified and passed to setChildren.
+	children(node N) []N
+
+	// setChildren replaces the node's children.
+	setChildren(node N, children []N)
+
+	// newNode returns a new, empty node for the same tree as like.
+	newNode(like N) N
+
+	// update brings up to date whatever the node records about its subtree, such as its height or size,
+	// after its keys or children have changed. The children of the node are always updated before it.
+	update(node N)
+}
+
+// pathStep is a node on the way down from the root of a B-tree, and the index of the node among its parent's children.
+type pathStep[N any] struct {
+	node  N
+	index int
+}
+
+// settlePath stores the keys and children in the node at the end of the path, which may be one key
+// too many for the order. A node that overflows is split around its middle key, which is moved up
+// into the parent together with the new right half of the node, and so on up the path.
+// Every node on the path is updated, and the halves of every split.
+// It returns the root of the tree, which is a new node if the root split.
+func settlePath[K any, N comparable](layout nodeLayout[K, N], order int, path []pathStep[N], keys []K, children []N) N {
+	for level := len(path) - 1; ; level-- {
+		node := path[level].node
+		if len(keys) < order {
+			layout.setKeys(node, keys)
+			layout.setChildren(node, children)
+			layout.update(node)
+			for level--; level >= 0; level-- {
+				layout.update(path[level].node)
+			}
+			return path[0].node
+		}
+
+		// Split around the middle key, which moves up to the parent.
+		m := len(keys) / 2
+		mid := keys[m]
+		right := layout.newNode(node)
+		layout.setKeys(right, append([]K(nil), keys[m+1:]...))
+		layout.setKeys(node, keys[:m:m])
+		if len(children) > 0 {
+			layout.setChildren(right, append([]N(nil), children[m+1:]...))
+			layout.setChildren(node, children[:m+1:m+1])
+		}
+		layout.update(node)
+		layout.update(right)
+
+		if level == 0 {
+			// The root split, so the tree grows a level.
+			root := layout.newNode(node)
+			layout.setKeys(root, []K{mid})
+			layout.setChildren(root, []N{node, right})
+			layout.update(root)
+			return root
+		}
+
+		// The middle key sits directly after node in the parent, and right directly after that.
+		parent := path[level-1].node
+		i := path[level].index
+		keys = insertAt(layout.keys(parent), i, mid)
+		children = insertAt(layout.children(parent), i+1, right)
+	}
+}
+
+// removePath removes the key at index i of the node at the end of the path.
+// Keys are only ever removed from leaves, so the key of an internal node is first replaced by its in-order
+// successor, which is removed from its leaf instead. A node left with fewer keys than the order allows
+// borrows a key from an adjacent sibling or merges with one, and so on up the path.
+// Every node on the path is updated, and every sibling a key is borrowed from.
+// It returns the root of the tree, which is the only child of the old root if the old root was emptied,
+// or the zero N if the tree has no keys left.
+func removePath[K any, N comparable](layout nodeLayout[K, N], order int, path []pathStep[N], i int) N {
+	node := path[len(path)-1].node
+	keys := layout.keys(node)
+	if children := layout.children(node); len(children) > 0 {
+		child := children[i+1]
+		path = append(path, pathStep[N]{node: child, index: i + 1})
+		for children = layout.children(child); len(children) > 0; children = layout.children(child) {
+			child = children[0]
+			path = append(path, pathStep[N]{node: child, index: 0})
+		}
+
+		successor := layout.keys(child)
+		keys[i] = successor[0]
+		layout.setKeys(node, keys)
+		node, keys, i = child, successor, 0
+	}
+
+	layout.setKeys(node, append(keys[:i], keys[i+1:]...))
+	layout.update(node)
+
+	minKeys := (order+1)/2 - 1
+	for level := len(path) - 1; level > 0; level-- {
+		parent := path[level-1].node
+		if len(layout.keys(path[level].node)) < minKeys {
+			repairChild(layout, minKeys, parent, path[level].index)
+		}
+		layout.update(parent)
+	}
+
+	var none N
+	root := path[0].node
+	if len(layout.keys(root)) > 0 {
+		return root
+	}
+	if children := layout.children(root); len(children) > 0 {
+		return children[0]
+	}
+	return none
+}
+
+// repairChild restores the child at i of the node after it has dropped below minKeys keys.
+// It borrows a key from an adjacent sibling that can spare one, and otherwise merges the child with a sibling,
+// which may leave node itself short of keys. The children it changes are updated, but not node.
+func repairChild[K any, N comparable](layout nodeLayout[K, N], minKeys int, node N, i int) {
+	keys, children := layout.keys(node), layout.children(node)
+	child := children[i]
+
+	// Borrow from the left sibling, rotating through the separator.
+	if i > 0 {
+		left := children[i-1]
+		if leftKeys := layout.keys(left); len(leftKeys) > minKeys {
+			last := len(leftKeys) - 1
+			layout.setKeys(child, insertAt(layout.keys(child), 0, keys[i-1]))
+			keys[i-1] = leftKeys[last]
+			layout.setKeys(node, keys)
+			layout.setKeys(left, leftKeys[:last])
+			if leftChildren := layout.children(left); len(leftChildren) > 0 {
+				layout.setChildren(child, insertAt(layout.children(child), 0, leftChildren[last+1]))
+				layout.setChildren(left, leftChildren[:last+1])
+			}
+			layout.update(left)
+			layout.update(child)
+			return
+		}
+	}
+
+	// Borrow from the right sibling, rotating through the separator.
+	if i < len(children)-1 {
+		right := children[i+1]
+		if rightKeys := layout.keys(right); len(rightKeys) > minKeys {
+			layout.setKeys(child, append(layout.keys(child), keys[i]))
+			keys[i] = rightKeys[0]
+			layout.setKeys(node, keys)
+			layout.setKeys(right, append(rightKeys[:0], rightKeys[1:]...))
+			if rightChildren := layout.children(right); len(rightChildren) > 0 {
+				layout.setChildren(child, append(layout.children(child), rightChildren[0]))
+				layout.setChildren(right, append(rightChildren[:0], rightChildren[1:]...))
+			}
+			layout.update(right)
+			layout.update(child)
+			return
+		}
+	}
+
+	// No sibling can spare a key, so merge with one, pulling the separator down.
+	if i == 0 {
+		i++
+	}
+	left, right := children[i-1], children[i]
+	layout.setKeys(left, append(append(layout.keys(left), keys[i-1]), layout.keys(right)...))
+	layout.setChildren(left, append(layout.children(left), layout.children(right)...))
+	layout.update(left)
+	layout.setKeys(node, append(keys[:i-1], keys[i:]...))
+	layout.setChildren(node, append(children[:i], children[i+1:]...))
+}
@@ -0,0 +1,119 @@
+package trees
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// sizedNode is a B-tree node that records the number of keys in its subtree, to check that the balancing
+// updates every node it changes, and updates children before their parents.
+type sizedNode struct {
+	keys     []int
+	children []*sizedNode
+	size     int
+}
+
+type sizedLayout struct{}
+
+func (sizedLayout) keys(node *sizedNode) []int                         { return node.keys }
+func (sizedLayout) setKeys(node *sizedNode, keys []int)                { node.keys = keys }
+func (sizedLayout) children(node *sizedNode) []*sizedNode              { return node.children }
+func (sizedLayout) setChildren(node *sizedNode, children []*sizedNode) { node.children = children }
+func (sizedLayout) newNode(*sizedNode) *sizedNode                      { return &sizedNode{} }
+
+func (sizedLayout) update(node *sizedNode) {
+	node.size = len(node.keys)
+	for _, child := range node.children {
+		node.size += child.size
+	}
+}
+
+// checkSizes fails the test if the size recorded by any node of the subtree is wrong.
+// It returns the number of keys in the subtree.
+func checkSizes(t *testing.T, node *sizedNode) int {
+	t.Helper()
+	size := len(node.keys)
+	for _, child := range node.children {
+		size += checkSizes(t, child)
+	}
+	if node.size != size {
+		t.Fatalf("node %v records size %d, want %d", node.keys, node.size, size)
+	}
+	return size
+}
+
+func sizedKeys(node *sizedNode) []int {
+	if node == nil {
+		return nil
+	}
+	var keys []int
+	for i, key := range node.keys {
+		if i < len(node.children) {
+			keys = append(keys, sizedKeys(node.children[i])...)
+		}
+		keys = append(keys, key)
+	}
+	if len(node.children) > 0 {
+		keys = append(keys, sizedKeys(node.children[len(node.children)-1])...)
+	}
+	return keys
+}
+
+func TestBalanceUpdatesChangedNodes(t *testing.T) {
+	for _, order := range []int{3, 4, 7} {
+		t.Run(fmt.Sprintf("It keeps every size up to date with order %d", order), func(t *testing.T) {
+			rng := rand.New(rand.NewSource(int64(order)))
+			var root *sizedNode
+			var want []int
+
+			for i := 0; i < 3000; i++ {
+				value := rng.Intn(300)
+				if root != nil && rng.Intn(3) == 0 {
+					var path []pathStep[*sizedNode]
+					for node, index := root, 0; node != nil; {
+						path = append(path, pathStep[*sizedNode]{node: node, index: index})
+						j := sort.SearchInts(node.keys, value)
+						if j < len(node.keys) && node.keys[j] == value {
+							root = removePath[int, *sizedNode](sizedLayout{}, order, path, j)
+							k := sort.SearchInts(want, value)
+							want = append(want[:k], want[k+1:]...)
+							break
+						}
+						if len(node.children) == 0 {
+							break
+						}
+						node, index = node.children[j], j
+					}
+				} else if root == nil {
+					root = &sizedNode{keys: []int{value}, size: 1}
+					want = []int{value}
+				} else {
+					path := []pathStep[*sizedNode]{{node: root}}
+					node := root
+					for len(node.children) > 0 {
+						j := sort.SearchInts(node.keys, value+1)
+						node = node.children[j]
+						path = append(path, pathStep[*sizedNode]{node: node, index: j})
+					}
+					keys := insertAt(node.keys, sort.SearchInts(node.keys, value+1), value)
+					root = settlePath[int, *sizedNode](sizedLayout{}, order, path, keys, nil)
+					want = insertAt(want, sort.SearchInts(want, value+1), value)
+				}
+
+				if root == nil {
+					if len(want) != 0 {
+						t.Fatalf("after step %d, the tree is empty, want %v", i, want)
+					}
+					continue
+				}
+				checkSizes(t, root)
+				if got := sizedKeys(root); !reflect.DeepEqual(got, want) {
+					t.Fatalf("after step %d, keys = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
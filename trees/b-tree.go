@@ -0,0 +1,384 @@
+package trees
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+/* BTree is a B-tree whose order, the most children a node may have, is chosen at construction.
+Every node other than the root holds between ceil(order/2)-1 and order-1 keys, and an internal
+node has one more child than keys. Keys and children are kept in slices, so wide nodes are searched
+by binary search and stay contiguous in memory.
+A BTree of order 3 builds the same shapes as TwoThreeTree.
+*/
+
+// ErrInvalidOrder is returned when constructing a BTree with an order below 3.
+var ErrInvalidOrder = errors.New("B-tree order must be at least 3")
+
+// bTreeNode is a node of a BTree.
+type bTreeNode[T any] struct {
+	// keys are the values held by the node, in ascending order.
+	keys []T
+
+	// children is empty for a leaf, and otherwise holds one more child than there are keys.
+	children []*bTreeNode[T]
+}
+
+func (node *bTreeNode[T]) isLeaf() bool {
+	return len(node.children) == 0
+}
+
+// bTreeLayout is the nodeLayout of bTreeNode.
+type bTreeLayout[T any] struct{}
+
+func (bTreeLayout[T]) keys(node *bTreeNode[T]) []T {
+	return node.keys
+}
+
+func (bTreeLayout[T]) setKeys(node *bTreeNode[T], keys []T) {
+	node.keys = keys
+}
+
+func (bTreeLayout[T]) children(node *bTreeNode[T]) []*bTreeNode[T] {
+	return node.children
+}
+
+func (bTreeLayout[T]) setChildren(node *bTreeNode[T], children []*bTreeNode[T]) {
+	node.children = children
+}
+
+func (bTreeLayout[T]) newNode(*bTreeNode[T]) *bTreeNode[T] {
+	return &bTreeNode[T]{}
+}
+
+// update does nothing, as a bTreeNode records nothing about its subtree.
+func (bTreeLayout[T]) update(*bTreeNode[T]) {}
+
+// BTree is a B-tree of a configurable order.
+// The zero value is not usable; call NewBTree.
+type BTree[T any] struct {
+	// root is nil while the tree is empty.
+	root *bTreeNode[T]
+
+	// comparator is used to compare two values.
+	// returns -1 if a < b, 0 if a == b, 1 if a > b
+	comparator func(T, T) int
+
+	// duplicates decides what Insert does with a value equal to one already in the tree.
+	duplicates DuplicatePolicy
+
+	// order is the most children a node may have.
+	order int
+
+	// size is the number of values stored in the tree.
+	size int
+}
+
+// NewBTree is a constructor for an empty B-tree of the given order, ordered by the given comparator.
+// The tree allows duplicate values.
+// It returns ErrInvalidOrder if the order is below 3.
+func NewBTree[T any](order int, comparator func(a, b T) int) (*BTree[T], error) {
+	return NewBTreeWithPolicy(order, comparator, AllowDuplicates)
+}
+
+// NewBTreeWithPolicy is a constructor for an empty B-tree of the given order, ordered by the given comparator,
+// which handles duplicate values according to the given policy.
+// It returns ErrInvalidOrder if the order is below 3.
+func NewBTreeWithPolicy[T any](order int, comparator func(a, b T) int, duplicates DuplicatePolicy) (*BTree[T], error) {
+	if order < 3 {
+		return nil, fmt.Errorf("%w: got %d", ErrInvalidOrder, order)
+	}
+	return &BTree[T]{
+		root:       nil,
+		comparator: comparator,
+		duplicates: duplicates,
+		order:      order,
+		size:       0,
+	}, nil
+}
+
+// minKeys returns the fewest keys a node other than the root may hold.
+func (tree *BTree[T]) minKeys() int {
+	return (tree.order+1)/2 - 1
+}
+
+// lowerBound returns the index of the first key of the node not less than value.
+func (tree *BTree[T]) lowerBound(node *bTreeNode[T], value T) int {
+	return sort.Search(len(node.keys), func(i int) bool {
+		return tree.comparator(node.keys[i], value) >= 0
+	})
+}
+
+// upperBound returns the index of the first key of the node greater than value.
+func (tree *BTree[T]) upperBound(node *bTreeNode[T], value T) int {
+	return sort.Search(len(node.keys), func(i int) bool {
+		return tree.comparator(node.keys[i], value) > 0
+	})
+}
+
+// find locates a key equal to value.
+// It returns the node holding it and its index, or nil if no key is equal to value.
+func (tree *BTree[T]) find(value T) (*bTreeNode[T], int) {
+	node := tree.root
+	for node != nil {
+		i := tree.lowerBound(node, value)
+		if i < len(node.keys) && tree.comparator(node.keys[i], value) == 0 {
+			return node, i
+		}
+		if node.isLeaf() {
+			break
+		}
+		node = node.children[i]
+	}
+	return nil, 0
+}
+
+// Order returns the most children a node of the tree may have.
+func (tree *BTree[T]) Order() int {
+	return tree.order
+}
+
+// Len returns the number of values stored in the tree.
+func (tree *BTree[T]) Len() int {
+	return tree.size
+}
+
+// Height returns the number of levels in the tree, or 0 if the tree is empty.
+func (tree *BTree[T]) Height() int {
+	height := 0
+	for node := tree.root; node != nil; height++ {
+		if node.isLeaf() {
+			node = nil
+		} else {
+			node = node.children[0]
+		}
+	}
+	return height
+}
+
+// Get finds a value in the tree.
+// It returns the stored value equal to the given one, and whether it was found.
+func (tree *BTree[T]) Get(value T) (T, bool) {
+	var zeroVal T
+	if node, i := tree.find(value); node != nil {
+		return node.keys[i], true
+	}
+	return zeroVal, false
+}
+
+// Contains reports whether a value equal to the given one is stored in the tree.
+func (tree *BTree[T]) Contains(value T) bool {
+	node, _ := tree.find(value)
+	return node != nil
+}
+
+// Insert inserts a value into the tree.
+// A value equal to one already in the tree is handled according to the tree's DuplicatePolicy.
+func (tree *BTree[T]) Insert(value T) error {
+	if tree.duplicates != AllowDuplicates {
+		if node, i := tree.find(value); node != nil {
+			if tree.duplicates == RejectDuplicates {
+				return ErrDuplicate
+			}
+			node.keys[i] = value
+			return nil
+		}
+	}
+
+	if tree.root == nil {
+		tree.root = &bTreeNode[T]{keys: []T{value}}
+		tree.size++
+		return nil
+	}
+
+	// Descend to the leaf the value belongs in, after any keys equal to it.
+	path := []pathStep[*bTreeNode[T]]{{node: tree.root}}
+	node := tree.root
+	for !node.isLeaf() {
+		i := tree.upperBound(node, value)
+		node = node.children[i]
+		path = append(path, pathStep[*bTreeNode[T]]{node: node, index: i})
+	}
+	keys := insertAt(node.keys, tree.upperBound(node, value), value)
+	tree.root = settlePath[T, *bTreeNode[T]](bTreeLayout[T]{}, tree.order, path, keys, nil)
+	tree.size++
+	return nil
+}
+
+// Delete removes a value from the tree.
+// It returns whether the value was found.
+func (tree *BTree[T]) Delete(value T) bool {
+	var path []pathStep[*bTreeNode[T]]
+	for node, index := tree.root, 0; node != nil; {
+		path = append(path, pathStep[*bTreeNode[T]]{node: node, index: index})
+		i := tree.lowerBound(node, value)
+		if i < len(node.keys) && tree.comparator(node.keys[i], value) == 0 {
+			tree.root = removePath[T, *bTreeNode[T]](bTreeLayout[T]{}, tree.order, path, i)
+			tree.size--
+			return true
+		}
+		if node.isLeaf() {
+			break
+		}
+		node, index = node.children[i], i
+	}
+	return false
+}
+
+// ascendRange calls fn, in ascending order, for each key of the subtree that lies within the range.
+// Bounds are nil when the range is unbounded on that side.
+// It returns false once fn returns false or a key above the range is reached.
+func (tree *BTree[T]) ascendRange(node *bTreeNode[T], lo, hi *T, inclusivity Inclusivity, fn func(T) bool) bool {
+	// Keys below the child that may hold lo are skipped by binary search.
+	start := 0
+	if lo != nil {
+		start = tree.lowerBound(node, *lo)
+	}
+
+	for i := start; i <= len(node.keys); i++ {
+		// child i only holds keys between keys[i-1] and keys[i].
+		if !node.isLeaf() && (hi == nil || i == 0 || tree.comparator(node.keys[i-1], *hi) <= 0) {
+			if !tree.ascendRange(node.children[i], lo, hi, inclusivity, fn) {
+				return false
+			}
+		}
+
+		if i == len(node.keys) {
+			break
+		}
+		if hi != nil && !belowHigh(tree.comparator, node.keys[i], *hi, inclusivity) {
+			return false
+		}
+		if (lo == nil || aboveLow(tree.comparator, node.keys[i], *lo, inclusivity)) && !fn(node.keys[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Ascend calls fn for each value of the tree in ascending order.
+// Iteration stops early if fn returns false.
+func (tree *BTree[T]) Ascend(fn func(T) bool) {
+	if tree.root != nil {
+		tree.ascendRange(tree.root, nil, nil, IncludeBoth, fn)
+	}
+}
+
+// AscendRange calls fn, in ascending order, for each value of the tree in the half-open range [lo, hi).
+// Iteration stops early if fn returns false.
+func (tree *BTree[T]) AscendRange(lo, hi T, fn func(T) bool) {
+	if tree.root != nil {
+		tree.ascendRange(tree.root, &lo, &hi, IncludeLow, fn)
+	}
+}
+
+// Range finds the values of the tree between lo and hi.
+// The inclusivity determines whether values equal to lo or hi are part of the range.
+// It returns the values in ascending order.
+func (tree *BTree[T]) Range(lo, hi T, inclusivity Inclusivity) []T {
+	var result []T
+	if tree.root != nil {
+		tree.ascendRange(tree.root, &lo, &hi, inclusivity, func(value T) bool {
+			result = append(result, value)
+			return true
+		})
+	}
+	return result
+}
+
+// InOrder returns the values of the tree in ascending order.
+func (tree *BTree[T]) InOrder() []T {
+	result := make([]T, 0, tree.size)
+	tree.Ascend(func(value T) bool {
+		result = append(result, value)
+		return true
+	})
+	return result
+}
+
+// BFS traverses the tree in breadth-first order.
+// It returns the keys of each node in the order visited.
+func (tree *BTree[T]) BFS() []T {
+	var result []T
+	if tree.root == nil {
+		return result
+	}
+	queue := []*bTreeNode[T]{tree.root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		result = append(result, node.keys...)
+		queue = append(queue, node.children...)
+	}
+	return result
+}
+
+// validate checks the subtree rooted at node, whose keys must lie between lo and hi (when not nil).
+func (tree *BTree[T]) validate(v *validator[T], node *bTreeNode[T], path string, depth int, lo, hi *T) {
+	v.count += len(node.keys)
+
+	if node != tree.root && len(node.keys) < tree.minKeys() {
+		v.report(path, "has %d key(s), want at least %d", len(node.keys), tree.minKeys())
+	}
+	if len(node.keys) == 0 {
+		v.report(path, "has no keys")
+	}
+	if len(node.keys) >= tree.order {
+		v.report(path, "has %d key(s), want at most %d", len(node.keys), tree.order-1)
+	}
+	if !node.isLeaf() && len(node.children) != len(node.keys)+1 {
+		v.report(path, "has %d key(s) and %d child(ren)", len(node.keys), len(node.children))
+	}
+
+	for i, key := range node.keys {
+		if i > 0 && tree.comparator(node.keys[i-1], key) > 0 {
+			v.report(path, "keys %v and %v are out of order", node.keys[i-1], key)
+		}
+		if lo != nil && tree.comparator(key, *lo) < 0 {
+			v.report(path, "key %v is less than the separator %v above it", key, *lo)
+		}
+		if hi != nil && tree.comparator(key, *hi) > 0 {
+			v.report(path, "key %v is greater than the separator %v above it", key, *hi)
+		}
+	}
+
+	if node.isLeaf() {
+		if v.leafDepth < 0 {
+			v.leafDepth = depth
+		} else if depth != v.leafDepth {
+			v.report(path, "leaf is at depth %d, want %d", depth, v.leafDepth)
+		}
+	}
+
+	for i, child := range node.children {
+		childLo, childHi := lo, hi
+		if i > 0 && i-1 < len(node.keys) {
+			childLo = &node.keys[i-1]
+		}
+		if i < len(node.keys) {
+			childHi = &node.keys[i]
+		}
+		tree.validate(v, child, fmt.Sprintf("%s.%d", path, i), depth+1, childLo, childHi)
+	}
+}
+
+// Validate walks the whole tree and checks every structural invariant of a B-tree:
+// each node other than the root holds between ceil(order/2)-1 and order-1 keys, an internal node
+// has one more child than keys, all leaves are at the same depth, keys are ordered within their node
+// and relative to the separators above them, and the tree holds Len keys.
+// It returns nil if the tree is valid, or a *ValidationError listing every violation.
+func (tree *BTree[T]) Validate() error {
+	v := &validator[T]{leafDepth: -1}
+	if tree.root != nil {
+		tree.validate(v, tree.root, "root", 0, nil, nil)
+	}
+	if v.count != tree.size {
+		v.report("root", "tree holds %d value(s), but Len is %d", v.count, tree.size)
+	}
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package trees
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func mustBTree(t testing.TB, order int, policy DuplicatePolicy) *BTree[int] {
+	t.Helper()
+	tree, err := NewBTreeWithPolicy(order, intComparator, policy)
+	if err != nil {
+		t.Fatalf("NewBTreeWithPolicy() error = %v", err)
+	}
+	return tree
+}
+
+func TestNewBTree(t *testing.T) {
+	if _, err := NewBTree(2, intComparator); !errors.Is(err, ErrInvalidOrder) {
+		t.Errorf("NewBTree(2) error = %v, want %v", err, ErrInvalidOrder)
+	}
+	tree, err := NewBTree(3, intComparator)
+	if err != nil || tree.Order() != 3 || tree.Len() != 0 || tree.Height() != 0 {
+		t.Errorf("NewBTree(3) = %v, %v, want an empty tree of order 3", tree, err)
+	}
+}
+
+func TestBTreeOrderThreeMatchesTwoThreeTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	twoThree := NewTwoThreeTree(intComparator)
+	bTree := mustBTree(t, 3, AllowDuplicates)
+
+	for i := 0; i < 2000; i++ {
+		value := rng.Intn(200)
+		if rng.Intn(3) == 0 {
+			found, err := twoThree.Delete(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bTree.Delete(value); got != found {
+				t.Fatalf("Delete(%d) = %v, want %v", value, got, found)
+			}
+		} else {
+			if err := twoThree.Insert(value); err != nil {
+				t.Fatal(err)
+			}
+			if err := bTree.Insert(value); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if got, want := bTree.BFS(), BFS(twoThree.Root()); !reflect.DeepEqual(got, want) {
+			t.Fatalf("after step %d, BFS() = %v, want %v", i, got, want)
+		}
+		if bTree.Height() != twoThree.Height() {
+			t.Fatalf("after step %d, Height() = %v, want %v", i, bTree.Height(), twoThree.Height())
+		}
+	}
+}
+
+func TestBTreeOrders(t *testing.T) {
+	for _, order := range []int{3, 4, 5, 8, 32, 256} {
+		t.Run(fmt.Sprintf("It stays valid with order %d", order), func(t *testing.T) {
+			rng := rand.New(rand.NewSource(int64(order)))
+			tree := mustBTree(t, order, AllowDuplicates)
+			var want []int
+
+			for i := 0; i < 5000; i++ {
+				value := rng.Intn(1000)
+				if rng.Intn(3) == 0 {
+					j := sort.SearchInts(want, value)
+					found := j < len(want) && want[j] == value
+					if found {
+						want = append(want[:j], want[j+1:]...)
+					}
+					if got := tree.Delete(value); got != found {
+						t.Fatalf("Delete(%d) = %v, want %v", value, got, found)
+					}
+				} else {
+					if err := tree.Insert(value); err != nil {
+						t.Fatal(err)
+					}
+					want = insertAt(want, sort.SearchInts(want, value+1), value)
+				}
+			}
+
+			if err := tree.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if got := tree.InOrder(); !reflect.DeepEqual(got, want) {
+				t.Errorf("InOrder() = %v, want %v", got, want)
+			}
+			for _, value := range []int{-1, 0, 500, 999, 1000} {
+				j := sort.SearchInts(want, value)
+				if got := tree.Contains(value); got != (j < len(want) && want[j] == value) {
+					t.Errorf("Contains(%d) = %v", value, got)
+				}
+			}
+
+			lo, hi := 250, 750
+			var wantRange []int
+			for _, value := range want {
+				if value >= lo && value < hi {
+					wantRange = append(wantRange, value)
+				}
+			}
+			var gotRange []int
+			tree.AscendRange(lo, hi, func(value int) bool {
+				gotRange = append(gotRange, value)
+				return true
+			})
+			if !reflect.DeepEqual(gotRange, wantRange) {
+				t.Errorf("AscendRange(%d, %d) = %v, want %v", lo, hi, gotRange, wantRange)
+			}
+
+			for _, value := range want {
+				if !tree.Delete(value) {
+					t.Fatalf("Delete(%d) did not find the value", value)
+				}
+			}
+			if tree.Len() != 0 || tree.Height() != 0 {
+				t.Errorf("tree is not empty after deleting every value")
+			}
+		})
+	}
+}
+
+func TestBTreeRange(t *testing.T) {
+	tree := mustBTree(t, 4, AllowDuplicates)
+	for _, value := range []int{5, 7, 8, 10, 15, 17, 20, 25, 35, 40, 45} {
+		if err := tree.Insert(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name        string
+		lo, hi      int
+		inclusivity Inclusivity
+		want        []int
+	}{
+		{name: "It includes both bounds", lo: 8, hi: 25, inclusivity: IncludeBoth, want: []int{8, 10, 15, 17, 20, 25}},
+		{name: "It excludes both bounds", lo: 8, hi: 25, inclusivity: ExcludeBoth, want: []int{10, 15, 17, 20}},
+		{name: "It handles bounds that are not in the tree", lo: 0, hi: 100, inclusivity: ExcludeBoth, want: []int{5, 7, 8, 10, 15, 17, 20, 25, 35, 40, 45}},
+		{name: "It returns nothing for an empty range", lo: 11, hi: 14, inclusivity: IncludeBoth, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tree.Range(tt.lo, tt.hi, tt.inclusivity); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Range() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBTreePolicies(t *testing.T) {
+	reject, err := NewBTreeWithPolicy(5, recordComparator, RejectDuplicates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replace, err := NewBTreeWithPolicy(5, recordComparator, ReplaceDuplicates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 30; i++ {
+		if err := reject.Insert(record{i, 0}); err != nil {
+			t.Fatal(err)
+		}
+		if err := replace.Insert(record{i, 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := reject.Insert(record{7, 1}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Insert() error = %v, want %v", err, ErrDuplicate)
+	}
+	if err := replace.Insert(record{7, 1}); err != nil {
+		t.Errorf("Insert() error = %v", err)
+	}
+	if got, _ := replace.Get(record{key: 7}); got.id != 1 {
+		t.Errorf("Get() = %v, want the replacement", got)
+	}
+	if reject.Len() != 30 || replace.Len() != 30 {
+		t.Errorf("Len() = %v, %v, want 30", reject.Len(), replace.Len())
+	}
+}
+
+func TestBTreeValidateReportsViolations(t *testing.T) {
+	tree := mustBTree(t, 3, AllowDuplicates)
+	for _, value := range []int{1, 2, 3, 4, 5} {
+		if err := tree.Insert(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tree.root.children[0].keys[0] = 9
+
+	var validationErr *ValidationError
+	if err := tree.Validate(); !errors.As(err, &validationErr) {
+		t.Fatalf("Validate() error = %v, want a *ValidationError", err)
+	}
+	if got := validationErr.Violations[0].Path; got != "root.0" {
+		t.Errorf("Validate() reported %v, want root.0", got)
+	}
+}
+
+func BenchmarkBTreeInsert(b *testing.B) {
+	values := rand.New(rand.NewSource(1)).Perm(100000)
+	for _, order := range []int{3, 32, 256} {
+		b.Run(fmt.Sprintf("order %d", order), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tree := mustBTree(b, order, AllowDuplicates)
+				for _, value := range values {
+					if err := tree.Insert(value); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBTreeGet(b *testing.B) {
+	values := rand.New(rand.NewSource(1)).Perm(100000)
+	for _, order := range []int{3, 32, 256} {
+		b.Run(fmt.Sprintf("order %d", order), func(b *testing.B) {
+			tree := mustBTree(b, order, AllowDuplicates)
+			for _, value := range values {
+				if err := tree.Insert(value); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tree.Get(values[i%len(values)])
+			}
+		})
+	}
+}
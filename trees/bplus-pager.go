@@ -0,0 +1,332 @@
+package trees
+
+import (
+	"container/list"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+/* A B+ tree file is a sequence of fixed-size pages. Page 0 holds the metadata:
+
+	magic      4 bytes  "BPT+"
+	version    uint16   bplusVersion
+	pageSize   uint32   the size of every page, in bytes
+	root       uint64   the page of the root node
+	pages      uint64   the number of pages in use, including page 0
+	count      uint64   the number of keys in the tree
+
+and every other page holds one node:
+
+	kind       byte     bplusLeaf or bplusInternal
+	keys       uint32   the number of keys
+	link       uint64   for a leaf, the page of the next leaf (0 for the last); otherwise the page of the first child
+	entries    for each key: a uvarint length, the key's bytes from the Codec, and for an internal node the page of the child after it
+
+Fixed-width integers are big-endian, and the rest of each page is zero.
+*/
+
+const (
+	bplusMagic   = "BPT+"
+	bplusVersion = 1
+
+	bplusMetaSize   = 4 + 2 + 4 + 8 + 8 + 8
+	bplusHeaderSize = 1 + 4 + 8
+
+	bplusMinPageSize = 128
+	bplusMaxPageSize = 1 << 20
+
+	bplusLeaf     byte = 1
+	bplusInternal byte = 2
+)
+
+// pageID is the index of a page within a B+ tree file. Page 0 holds the metadata, so 0 also means "no page".
+type pageID uint64
+
+// bplusMeta is the metadata stored in page 0.
+type bplusMeta struct {
+	pageSize uint32
+	root     pageID
+	pages    uint64
+	count    uint64
+}
+
+func (meta bplusMeta) encode() []byte {
+	page := make([]byte, meta.pageSize)
+	copy(page, bplusMagic)
+	binary.BigEndian.PutUint16(page[4:], bplusVersion)
+	binary.BigEndian.PutUint32(page[6:], meta.pageSize)
+	binary.BigEndian.PutUint64(page[10:], uint64(meta.root))
+	binary.BigEndian.PutUint64(page[18:], meta.pages)
+	binary.BigEndian.PutUint64(page[26:], meta.count)
+	return page
+}
+
+func decodeMeta(page []byte) (bplusMeta, error) {
+	if len(page) < bplusMetaSize || string(page[:4]) != bplusMagic {
+		return bplusMeta{}, fmt.Errorf("%w: bad magic", ErrInvalidFormat)
+	}
+	if version := binary.BigEndian.Uint16(page[4:]); version != bplusVersion {
+		return bplusMeta{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+	meta := bplusMeta{
+		pageSize: binary.BigEndian.Uint32(page[6:]),
+		root:     pageID(binary.BigEndian.Uint64(page[10:])),
+		pages:    binary.BigEndian.Uint64(page[18:]),
+		count:    binary.BigEndian.Uint64(page[26:]),
+	}
+	if meta.pageSize < bplusMinPageSize || meta.pageSize > bplusMaxPageSize {
+		return bplusMeta{}, fmt.Errorf("%w: page size %d", ErrInvalidFormat, meta.pageSize)
+	}
+	if meta.root == 0 || uint64(meta.root) >= meta.pages {
+		return bplusMeta{}, fmt.Errorf("%w: root page %d of %d", ErrInvalidFormat, meta.root, meta.pages)
+	}
+	return meta, nil
+}
+
+// bplusNode is the decoded form of a node page, held by the buffer pool.
+type bplusNode[T any] struct {
+	id   pageID
+	leaf bool
+
+	// keys are the node's keys in ascending order, and raw their encodings.
+	keys []T
+	raw  [][]byte
+
+	// children are the pages of an internal node's children, one more than its keys.
+	children []pageID
+
+	// next is the page of the next leaf, or 0 for the last leaf and for internal nodes.
+	next pageID
+
+	// size is the number of bytes the node's page needs, kept up to date as keys are added and removed.
+	size int
+
+	// dirty is set when the node differs from its page in the file.
+	dirty bool
+
+	// pins counts the operations using the node, which may not be evicted while it is pinned.
+	pins int
+
+	// lru is the node's place in the buffer pool's recency list.
+	lru *list.Element
+}
+
+// encodedSize computes the number of bytes the node's page needs, which size tracks.
+func (node *bplusNode[T]) encodedSize() int {
+	size := bplusHeaderSize
+	for _, raw := range node.raw {
+		size += entrySize(raw, node.leaf)
+	}
+	return size
+}
+
+// entrySize returns the number of bytes a key takes up in a page.
+func entrySize(raw []byte, leaf bool) int {
+	var prefix [binary.MaxVarintLen64]byte
+	size := binary.PutUvarint(prefix[:], uint64(len(raw))) + len(raw)
+	if !leaf {
+		size += 8
+	}
+	return size
+}
+
+func (node *bplusNode[T]) encode(pageSize uint32) []byte {
+	page := make([]byte, pageSize)
+	page[0] = bplusInternal
+	link := node.next
+	if node.leaf {
+		page[0] = bplusLeaf
+	} else {
+		link = node.children[0]
+	}
+	binary.BigEndian.PutUint32(page[1:], uint32(len(node.keys)))
+	binary.BigEndian.PutUint64(page[5:], uint64(link))
+
+	at := bplusHeaderSize
+	for i, raw := range node.raw {
+		at += binary.PutUvarint(page[at:], uint64(len(raw)))
+		at += copy(page[at:], raw)
+		if !node.leaf {
+			binary.BigEndian.PutUint64(page[at:], uint64(node.children[i+1]))
+			at += 8
+		}
+	}
+	return page
+}
+
+func decodeNode[T any](id pageID, page []byte, codec Codec[T]) (*bplusNode[T], error) {
+	corrupt := func(reason string) error {
+		return fmt.Errorf("%w: page %d %s", ErrInvalidFormat, id, reason)
+	}
+
+	if len(page) < bplusHeaderSize {
+		return nil, corrupt("is shorter than a node header")
+	}
+	kind := page[0]
+	if kind != bplusLeaf && kind != bplusInternal {
+		return nil, corrupt("is not a node")
+	}
+	n := int(binary.BigEndian.Uint32(page[1:]))
+	link := pageID(binary.BigEndian.Uint64(page[5:]))
+
+	node := &bplusNode[T]{id: id, leaf: kind == bplusLeaf}
+	if node.leaf {
+		node.next = link
+	} else {
+		node.children = append(node.children, link)
+	}
+
+	at := bplusHeaderSize
+	for i := 0; i < n; i++ {
+		length, read := binary.Uvarint(page[at:])
+		if read <= 0 || uint64(len(page)-at-read) < length {
+			return nil, corrupt("has a key overrunning the page")
+		}
+		at += read
+		raw := append([]byte(nil), page[at:at+int(length)]...)
+		at += int(length)
+
+		key, err := codec.Decode(raw)
+		if err != nil {
+			return nil, err
+		}
+		node.keys = append(node.keys, key)
+		node.raw = append(node.raw, raw)
+
+		if !node.leaf {
+			if len(page)-at < 8 {
+				return nil, corrupt("has a child overrunning the page")
+			}
+			node.children = append(node.children, pageID(binary.BigEndian.Uint64(page[at:])))
+			at += 8
+		}
+	}
+	node.size = at
+	return node, nil
+}
+
+// bufferPool caches decoded nodes of a page file, writing dirty nodes back when they are evicted or flushed.
+// It evicts the least recently used unpinned node once it holds more than capacity nodes;
+// when every node is pinned it grows past its capacity instead.
+type bufferPool[T any] struct {
+	file     *os.File
+	codec    Codec[T]
+	pageSize uint32
+	capacity int
+
+	nodes  map[pageID]*bplusNode[T]
+	recent *list.List
+}
+
+func newBufferPool[T any](file *os.File, codec Codec[T], pageSize uint32, capacity int) *bufferPool[T] {
+	return &bufferPool[T]{
+		file:     file,
+		codec:    codec,
+		pageSize: pageSize,
+		capacity: capacity,
+		nodes:    map[pageID]*bplusNode[T]{},
+		recent:   list.New(),
+	}
+}
+
+func (pool *bufferPool[T]) readPage(id pageID) ([]byte, error) {
+	page := make([]byte, pool.pageSize)
+	if _, err := pool.file.ReadAt(page, int64(id)*int64(pool.pageSize)); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: page %d is past the end of the file", ErrInvalidFormat, id)
+		}
+		return nil, err
+	}
+	return page, nil
+}
+
+func (pool *bufferPool[T]) writePage(id pageID, page []byte) error {
+	_, err := pool.file.WriteAt(page, int64(id)*int64(pool.pageSize))
+	return err
+}
+
+// reserve evicts unpinned nodes until n more can be cached without evicting any, or every node left is pinned.
+// If an evicted node cannot be written back, it returns the error; the nodes evicted before it stay evicted,
+// which changes nothing but what is cached.
+func (pool *bufferPool[T]) reserve(n int) error {
+	for e := pool.recent.Back(); e != nil && len(pool.nodes)+n > pool.capacity; {
+		victim := e.Value.(*bplusNode[T])
+		e = e.Prev()
+		if victim.pins > 0 {
+			continue
+		}
+		if victim.dirty {
+			if err := pool.writePage(victim.id, victim.encode(pool.pageSize)); err != nil {
+				return err
+			}
+		}
+		pool.recent.Remove(victim.lru)
+		delete(pool.nodes, victim.id)
+	}
+	return nil
+}
+
+// add caches a node, pinned, first evicting others if the pool is at capacity.
+// If an evicted node cannot be written back, it returns the error without caching the node.
+func (pool *bufferPool[T]) add(node *bplusNode[T]) error {
+	if err := pool.reserve(1); err != nil {
+		return err
+	}
+
+	node.pins = 1
+	node.lru = pool.recent.PushFront(node)
+	pool.nodes[node.id] = node
+	return nil
+}
+
+// get returns the node stored in the page, pinned. It must be released once the caller is done with it.
+func (pool *bufferPool[T]) get(id pageID) (*bplusNode[T], error) {
+	if node, ok := pool.nodes[id]; ok {
+		node.pins++
+		pool.recent.MoveToFront(node.lru)
+		return node, nil
+	}
+
+	page, err := pool.readPage(id)
+	if err != nil {
+		return nil, err
+	}
+	node, err := decodeNode(id, page, pool.codec)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.add(node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// create caches a new, empty node for the page, pinned and dirty.
+func (pool *bufferPool[T]) create(id pageID, leaf bool) (*bplusNode[T], error) {
+	node := &bplusNode[T]{id: id, leaf: leaf, size: bplusHeaderSize, dirty: true}
+	if err := pool.add(node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// release unpins a node returned by get or create.
+func (pool *bufferPool[T]) release(node *bplusNode[T]) {
+	node.pins--
+}
+
+// flush writes every dirty node back to the file.
+func (pool *bufferPool[T]) flush() error {
+	for _, node := range pool.nodes {
+		if !node.dirty {
+			continue
+		}
+		if err := pool.writePage(node.id, node.encode(pool.pageSize)); err != nil {
+			return err
+		}
+		node.dirty = false
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package trees
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBPlusNodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		node *bplusNode[int]
+	}{
+		{
+			name: "It round trips a leaf with its next link",
+			node: &bplusNode[int]{id: 3, leaf: true, keys: []int{-5, 0, 300}, next: 9},
+		},
+		{
+			name: "It round trips an internal node with its children",
+			node: &bplusNode[int]{id: 4, keys: []int{10, 20}, children: []pageID{1, 2, 5}},
+		},
+		{
+			name: "It round trips an empty leaf",
+			node: &bplusNode[int]{id: 5, leaf: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range tt.node.keys {
+				raw, _ := IntCodec{}.Encode(nil, key)
+				tt.node.raw = append(tt.node.raw, raw)
+			}
+
+			page := tt.node.encode(128)
+			if len(page) != 128 {
+				t.Fatalf("encode() is %d bytes, want 128", len(page))
+			}
+			got, err := decodeNode[int](tt.node.id, page, IntCodec{})
+			if err != nil {
+				t.Fatalf("decodeNode() error = %v", err)
+			}
+			if got.leaf != tt.node.leaf || got.next != tt.node.next ||
+				!reflect.DeepEqual(got.keys, tt.node.keys) || !reflect.DeepEqual(got.children, tt.node.children) {
+				t.Errorf("decodeNode() = %+v, want %+v", got, tt.node)
+			}
+		})
+	}
+
+	if _, err := decodeNode[int](1, make([]byte, 128), IntCodec{}); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("decodeNode() of a zero page error = %v, want %v", err, ErrInvalidFormat)
+	}
+	if _, err := decodeNode[int](1, nil, IntCodec{}); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("decodeNode() of an empty page error = %v, want %v", err, ErrInvalidFormat)
+	}
+}
+
+func TestBufferPoolEvictsDirtyNodes(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "pages"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	pool := newBufferPool[int](file, IntCodec{}, 128, 2)
+
+	for id := pageID(1); id <= 4; id++ {
+		node, err := pool.create(id, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, _ := IntCodec{}.Encode(nil, int(id))
+		node.keys, node.raw = []int{int(id)}, [][]byte{raw}
+		pool.release(node)
+	}
+	if len(pool.nodes) != 2 {
+		t.Errorf("pool holds %d nodes, want its capacity of 2", len(pool.nodes))
+	}
+
+	// Page 1 was evicted, so it must be read back from the file.
+	node, err := pool.get(1)
+	if err != nil {
+		t.Fatalf("get(1) error = %v", err)
+	}
+	if !reflect.DeepEqual(node.keys, []int{1}) || node.dirty {
+		t.Errorf("get(1) = %+v, want a clean node holding 1", node)
+	}
+
+	// A pinned node is never evicted, so the pool grows instead.
+	for id := pageID(2); id <= 4; id++ {
+		if _, err := pool.get(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(pool.nodes) != 4 {
+		t.Errorf("pool holds %d nodes, want all 4 pinned nodes", len(pool.nodes))
+	}
+}
+
+func TestBufferPoolWriteBackFailure(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "pages"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := newBufferPool[int](file, IntCodec{}, 128, 2)
+	for id := pageID(1); id <= 2; id++ {
+		node, err := pool.create(id, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool.release(node)
+	}
+
+	// Writing back the dirty node evicted for page 3 fails once the file is closed.
+	file.Close()
+	if node, err := pool.create(3, true); err == nil || node != nil {
+		t.Fatalf("create(3) = %v, %v, want an error", node, err)
+	}
+	if _, ok := pool.nodes[3]; ok {
+		t.Error("pool caches the node it failed to make room for")
+	}
+	for id, node := range pool.nodes {
+		if node.pins != 0 {
+			t.Errorf("page %d has %d pin(s) left", id, node.pins)
+		}
+	}
+}
+
+func TestBPlusTreeReleasesNodes(t *testing.T) {
+	tree := openInts(t, filepath.Join(t.TempDir(), "index"), BPlusOptions{PageSize: 128, PoolSize: 4})
+	defer tree.Close()
+	for i := 0; i < 500; i++ {
+		if err := tree.Insert(i % 50); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 50; i += 2 {
+		if _, err := tree.Delete(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := tree.Range(10, 20, IncludeBoth); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, node := range tree.pool.nodes {
+		if node.pins != 0 {
+			t.Errorf("page %d has %d pin(s) left", id, node.pins)
+		}
+		if node.size != node.encodedSize() {
+			t.Errorf("page %d has size %d, want %d", id, node.size, node.encodedSize())
+		}
+	}
+	if len(tree.pool.nodes) > 4 {
+		t.Errorf("pool holds %d nodes, want at most 4", len(tree.pool.nodes))
+	}
+}
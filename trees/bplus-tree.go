@@ -0,0 +1,497 @@
+package trees
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+var (
+	// ErrClosed is returned by the methods of a tree backed by files, such as a BPlusTree, after it is closed.
+	ErrClosed = errors.New("tree is closed")
+
+	// ErrKeyTooLarge is returned when inserting a key whose encoding would not leave room for others in its page.
+	ErrKeyTooLarge = errors.New("key is too large for the page size")
+)
+
+// BPlusOptions configures a BPlusTree opened by OpenBPlusTree. Zero fields take their default.
+type BPlusOptions struct {
+	// PageSize is the size of each page in bytes, 4096 by default.
+	// It only applies when a file is created; an existing file keeps the page size it was created with.
+	PageSize int
+
+	// PoolSize is the number of pages the buffer pool caches, 256 by default.
+	PoolSize int
+
+	// Duplicates decides what Insert does with a key equal to one already in the tree.
+	Duplicates DuplicatePolicy
+}
+
+// BPlusTree is a B+ tree stored in a file of fixed-size pages, for indexes larger than memory.
+// Keys live only in the leaves, which are linked in ascending order for range scans; internal nodes
+// hold copies of keys to guide searches. Nodes are cached in a buffer pool and written back when evicted,
+// and by Sync and Close, so the file is only consistent after one of those returns.
+// Delete removes keys without merging the pages they leave underfull, which never invalidates a search,
+// so the file does not shrink.
+// A BPlusTree is not safe for concurrent use.
+type BPlusTree[T any] struct {
+	file *os.File
+	pool *bufferPool[T]
+	meta bplusMeta
+
+	// comparator is used to compare two keys.
+	// returns -1 if a < b, 0 if a == b, 1 if a > b
+	comparator func(T, T) int
+
+	codec      Codec[T]
+	duplicates DuplicatePolicy
+
+	// maxEntry is the most bytes one key may take in a page, so that a split always yields two pages that fit.
+	maxEntry int
+
+	closed bool
+}
+
+// OpenBPlusTree opens the B+ tree stored in the file at path, creating an empty tree if the file does not exist.
+// Keys are ordered by the comparator and stored in their pages with the codec; both must match those used
+// when the file was written.
+// It returns ErrInvalidFormat or ErrUnsupportedVersion (possibly wrapped) if the file is not a B+ tree it can read.
+func OpenBPlusTree[T any](path string, comparator func(a, b T) int, codec Codec[T], opts BPlusOptions) (*BPlusTree[T], error) {
+	if opts.PageSize == 0 {
+		opts.PageSize = 4096
+	}
+	if opts.PoolSize == 0 {
+		opts.PoolSize = 256
+	}
+	if opts.PageSize < bplusMinPageSize || opts.PageSize > bplusMaxPageSize {
+		return nil, fmt.Errorf("page size %d is not between %d and %d", opts.PageSize, bplusMinPageSize, bplusMaxPageSize)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &BPlusTree[T]{
+		file:       file,
+		comparator: comparator,
+		codec:      codec,
+		duplicates: opts.Duplicates,
+	}
+	if err := tree.init(opts); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return tree, nil
+}
+
+// init reads the metadata of the file, or writes that of an empty tree if the file is new.
+func (tree *BPlusTree[T]) init(opts BPlusOptions) error {
+	info, err := tree.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.Size() == 0 {
+		tree.meta = bplusMeta{pageSize: uint32(opts.PageSize), root: 1, pages: 2}
+		tree.pool = newBufferPool(tree.file, tree.codec, tree.meta.pageSize, opts.PoolSize)
+		root, err := tree.pool.create(tree.meta.root, true)
+		if err != nil {
+			return err
+		}
+		tree.pool.release(root)
+		tree.maxEntry = (opts.PageSize - bplusHeaderSize) / 4
+		return tree.Sync()
+	}
+
+	page := make([]byte, bplusMetaSize)
+	if _, err := tree.file.ReadAt(page, 0); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	if tree.meta, err = decodeMeta(page); err != nil {
+		return err
+	}
+	tree.pool = newBufferPool(tree.file, tree.codec, tree.meta.pageSize, opts.PoolSize)
+	tree.maxEntry = (int(tree.meta.pageSize) - bplusHeaderSize) / 4
+	return nil
+}
+
+// Sync writes every change to the file and flushes it to stable storage.
+func (tree *BPlusTree[T]) Sync() error {
+	if tree.closed {
+		return ErrClosed
+	}
+	if err := tree.pool.flush(); err != nil {
+		return err
+	}
+	if _, err := tree.file.WriteAt(tree.meta.encode(), 0); err != nil {
+		return err
+	}
+	return tree.file.Sync()
+}
+
+// Close syncs the tree and closes its file. The tree may not be used afterwards.
+func (tree *BPlusTree[T]) Close() error {
+	if tree.closed {
+		return ErrClosed
+	}
+	err := tree.Sync()
+	tree.closed = true
+	if closeErr := tree.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Len returns the number of keys stored in the tree.
+func (tree *BPlusTree[T]) Len() int {
+	return int(tree.meta.count)
+}
+
+// lowerBound returns the index of the first key of the node not less than value.
+func (tree *BPlusTree[T]) lowerBound(node *bplusNode[T], value T) int {
+	return sort.Search(len(node.keys), func(i int) bool {
+		return tree.comparator(node.keys[i], value) >= 0
+	})
+}
+
+// upperBound returns the index of the first key of the node greater than value.
+func (tree *BPlusTree[T]) upperBound(node *bplusNode[T], value T) int {
+	return sort.Search(len(node.keys), func(i int) bool {
+		return tree.comparator(node.keys[i], value) > 0
+	})
+}
+
+// seek returns the first leaf that may hold a key not less than value, pinned, with the index of that key.
+// Keys of a child lie between the separators either side of it, inclusive, so the search descends
+// to the left of any separator equal to value; the key may then be in a later leaf, or in none.
+func (tree *BPlusTree[T]) seek(value T) (*bplusNode[T], int, error) {
+	node, err := tree.pool.get(tree.meta.root)
+	if err != nil {
+		return nil, 0, err
+	}
+	for !node.leaf {
+		child, err := tree.pool.get(node.children[tree.lowerBound(node, value)])
+		tree.pool.release(node)
+		if err != nil {
+			return nil, 0, err
+		}
+		node = child
+	}
+	return node, tree.lowerBound(node, value), nil
+}
+
+// scan calls fn for each key from the index of the pinned leaf onwards, following the links between leaves.
+// It releases the leaf, and returns the first error reading a leaf.
+func (tree *BPlusTree[T]) scan(leaf *bplusNode[T], i int, fn func(leaf *bplusNode[T], i int) bool) error {
+	for {
+		for ; i < len(leaf.keys); i++ {
+			if !fn(leaf, i) {
+				tree.pool.release(leaf)
+				return nil
+			}
+		}
+
+		next := leaf.next
+		tree.pool.release(leaf)
+		if next == 0 {
+			return nil
+		}
+
+		var err error
+		if leaf, err = tree.pool.get(next); err != nil {
+			return err
+		}
+		i = 0
+	}
+}
+
+// Get finds a key in the tree.
+// It returns the stored key equal to the given one, and whether it was found.
+func (tree *BPlusTree[T]) Get(value T) (T, bool, error) {
+	var found T
+	var ok bool
+	if tree.closed {
+		return found, false, ErrClosed
+	}
+
+	leaf, i, err := tree.seek(value)
+	if err != nil {
+		return found, false, err
+	}
+	err = tree.scan(leaf, i, func(leaf *bplusNode[T], i int) bool {
+		if tree.comparator(leaf.keys[i], value) == 0 {
+			found, ok = leaf.keys[i], true
+		}
+		return false
+	})
+	return found, ok, err
+}
+
+// Contains reports whether a key equal to the given one is stored in the tree.
+func (tree *BPlusTree[T]) Contains(value T) (bool, error) {
+	_, ok, err := tree.Get(value)
+	return ok, err
+}
+
+// allocate returns a new node in a page at the end of the file, pinned.
+// The page is only taken once the node is cached.
+func (tree *BPlusTree[T]) allocate(leaf bool) (*bplusNode[T], error) {
+	node, err := tree.pool.create(pageID(tree.meta.pages), leaf)
+	if err != nil {
+		return nil, err
+	}
+	tree.meta.pages++
+	return node, nil
+}
+
+// split moves the upper half of an overflowing node, by size, into the empty node right.
+// It returns the separator to put before right in the parent, and its encoding.
+func (tree *BPlusTree[T]) split(node, right *bplusNode[T]) (T, []byte) {
+	// Split where the entries before reach half the size of the page.
+	half, m := node.size/2, 0
+	for size := bplusHeaderSize; m < len(node.raw)-1 && size < half; m++ {
+		size += entrySize(node.raw[m], node.leaf)
+	}
+	if m == 0 {
+		m = 1
+	}
+
+	if node.leaf {
+		// A leaf keeps its keys, and a copy of the first key of the right half becomes the separator.
+		right.keys = append(right.keys, node.keys[m:]...)
+		right.raw = append(right.raw, node.raw[m:]...)
+		right.next, node.next = node.next, right.id
+		node.keys, node.raw = node.keys[:m:m], node.raw[:m:m]
+		node.size, right.size = node.encodedSize(), right.encodedSize()
+		node.dirty = true
+		return right.keys[0], right.raw[0]
+	}
+
+	// An internal node's middle key moves up to the parent.
+	sep, raw := node.keys[m], node.raw[m]
+	right.keys = append(right.keys, node.keys[m+1:]...)
+	right.raw = append(right.raw, node.raw[m+1:]...)
+	right.children = append(right.children, node.children[m+1:]...)
+	node.keys, node.raw, node.children = node.keys[:m:m], node.raw[:m:m], node.children[:m+1:m+1]
+	node.size, right.size = node.encodedSize(), right.encodedSize()
+	node.dirty = true
+	return sep, raw
+}
+
+// descend returns the nodes from the root down to the leaf a key belongs in, after any keys equal to it, pinned.
+func (tree *BPlusTree[T]) descend(value T) ([]*bplusNode[T], error) {
+	node, err := tree.pool.get(tree.meta.root)
+	if err != nil {
+		return nil, err
+	}
+	path := []*bplusNode[T]{node}
+	for !node.leaf {
+		if node, err = tree.pool.get(node.children[tree.upperBound(node, value)]); err != nil {
+			for _, node := range path {
+				tree.pool.release(node)
+			}
+			return nil, err
+		}
+		path = append(path, node)
+	}
+	return path, nil
+}
+
+// newNodes returns the most nodes that adding an entry of the given size to the leaf at the end of the path
+// can create: one for each node that may split, and one more for a new root if the root may split.
+// A separator passed up to a parent takes at most maxEntry bytes.
+func (tree *BPlusTree[T]) newNodes(path []*bplusNode[T], grow int) int {
+	n := 0
+	for level := len(path) - 1; level >= 0; level-- {
+		if path[level].size+grow <= int(tree.meta.pageSize) {
+			return n
+		}
+		n++
+		grow = tree.maxEntry
+	}
+	return n + 1
+}
+
+// Insert inserts a key into the tree.
+// A key equal to one already in the tree is handled according to the tree's DuplicatePolicy.
+// It returns an error wrapping ErrKeyTooLarge if the key's encoding takes more than a quarter of a page.
+// If a page cannot be read or written back, it returns the error and leaves the tree unchanged.
+func (tree *BPlusTree[T]) Insert(value T) error {
+	if tree.closed {
+		return ErrClosed
+	}
+	raw, err := tree.codec.Encode(nil, value)
+	if err != nil {
+		return err
+	}
+	if entrySize(raw, false) > tree.maxEntry {
+		return fmt.Errorf("%w: %d bytes", ErrKeyTooLarge, len(raw))
+	}
+
+	path, err := tree.descend(value)
+	if err != nil {
+		return err
+	}
+	pinned := path
+	defer func() {
+		for _, node := range pinned {
+			tree.pool.release(node)
+		}
+	}()
+
+	leaf := path[len(path)-1]
+	i := tree.upperBound(leaf, value)
+	replace := tree.duplicates != AllowDuplicates && i > 0 && tree.comparator(leaf.keys[i-1], value) == 0
+	if replace && tree.duplicates == RejectDuplicates {
+		return ErrDuplicate
+	}
+
+	// Make room in the buffer pool for every node the splits may create before changing anything,
+	// so a page that cannot be written back fails the insert while the tree is untouched.
+	// The path and the new nodes stay pinned until the insert is done, so nothing is evicted after this.
+	if err := tree.pool.reserve(tree.newNodes(path, entrySize(raw, true))); err != nil {
+		return err
+	}
+
+	if replace {
+		leaf.size += entrySize(raw, true) - entrySize(leaf.raw[i-1], true)
+		leaf.keys[i-1], leaf.raw[i-1] = value, raw
+	} else {
+		leaf.keys = insertAt(leaf.keys, i, value)
+		leaf.raw = insertAt(leaf.raw, i, raw)
+		leaf.size += entrySize(raw, true)
+	}
+	leaf.dirty = true
+
+	for level := len(path) - 1; level >= 0 && path[level].size > int(tree.meta.pageSize); level-- {
+		node := path[level]
+		right, err := tree.allocate(node.leaf)
+		if err != nil {
+			return err
+		}
+		pinned = append(pinned, right)
+		sep, sepRaw := tree.split(node, right)
+
+		if level == 0 {
+			// The root split, so the tree grows a level.
+			root, err := tree.allocate(false)
+			if err != nil {
+				return err
+			}
+			pinned = append(pinned, root)
+			root.keys = []T{sep}
+			root.raw = [][]byte{sepRaw}
+			root.children = []pageID{node.id, right.id}
+			root.size = root.encodedSize()
+			tree.meta.root = root.id
+			break
+		}
+
+		// The parent's keys have not changed since the descent, so the same search finds the child's place.
+		parent := path[level-1]
+		j := tree.upperBound(parent, value)
+		parent.keys = insertAt(parent.keys, j, sep)
+		parent.raw = insertAt(parent.raw, j, sepRaw)
+		parent.children = insertAt(parent.children, j+1, right.id)
+		parent.size += entrySize(sepRaw, false)
+		parent.dirty = true
+	}
+
+	if !replace {
+		tree.meta.count++
+	}
+	return nil
+}
+
+// Delete removes a key from the tree.
+// It returns whether the key was found.
+func (tree *BPlusTree[T]) Delete(value T) (bool, error) {
+	if tree.closed {
+		return false, ErrClosed
+	}
+
+	leaf, i, err := tree.seek(value)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	err = tree.scan(leaf, i, func(leaf *bplusNode[T], i int) bool {
+		if tree.comparator(leaf.keys[i], value) == 0 {
+			leaf.keys = append(leaf.keys[:i], leaf.keys[i+1:]...)
+			leaf.size -= entrySize(leaf.raw[i], true)
+			leaf.raw = append(leaf.raw[:i], leaf.raw[i+1:]...)
+			leaf.dirty = true
+			found = true
+		}
+		return false
+	})
+	if found {
+		tree.meta.count--
+	}
+	return found, err
+}
+
+// ascendRange calls fn, in ascending order, for each key of the tree that lies within the range.
+func (tree *BPlusTree[T]) ascendRange(lo, hi T, inclusivity Inclusivity, fn func(T) bool) error {
+	if tree.closed {
+		return ErrClosed
+	}
+
+	leaf, i, err := tree.seek(lo)
+	if err != nil {
+		return err
+	}
+	return tree.scan(leaf, i, func(leaf *bplusNode[T], i int) bool {
+		key := leaf.keys[i]
+		if !aboveLow(tree.comparator, key, lo, inclusivity) {
+			return true
+		}
+		return belowHigh(tree.comparator, key, hi, inclusivity) && fn(key)
+	})
+}
+
+// AscendRange calls fn, in ascending order, for each key of the tree in the half-open range [lo, hi).
+// Iteration stops early if fn returns false.
+// It returns the first error reading the tree.
+func (tree *BPlusTree[T]) AscendRange(lo, hi T, fn func(T) bool) error {
+	return tree.ascendRange(lo, hi, IncludeLow, fn)
+}
+
+// Range finds the keys of the tree between lo and hi.
+// The inclusivity decides whether keys equal to lo or hi are included.
+// It returns the keys found in ascending order, and the first error reading the tree.
+func (tree *BPlusTree[T]) Range(lo, hi T, inclusivity Inclusivity) ([]T, error) {
+	var keys []T
+	err := tree.ascendRange(lo, hi, inclusivity, func(key T) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys, err
+}
+
+// Ascend calls fn for each key of the tree in ascending order.
+// Iteration stops early if fn returns false.
+// It returns the first error reading the tree.
+func (tree *BPlusTree[T]) Ascend(fn func(T) bool) error {
+	if tree.closed {
+		return ErrClosed
+	}
+
+	node, err := tree.pool.get(tree.meta.root)
+	if err != nil {
+		return err
+	}
+	for !node.leaf {
+		child, err := tree.pool.get(node.children[0])
+		tree.pool.release(node)
+		if err != nil {
+			return err
+		}
+		node = child
+	}
+	return tree.scan(node, 0, func(leaf *bplusNode[T], i int) bool {
+		return fn(leaf.keys[i])
+	})
+}
@@ -0,0 +1,359 @@
+package trees
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func openInts(t *testing.T, path string, opts BPlusOptions) *BPlusTree[int] {
+	t.Helper()
+	tree, err := OpenBPlusTree[int](path, intComparator, IntCodec{}, opts)
+	if err != nil {
+		t.Fatalf("OpenBPlusTree() error = %v", err)
+	}
+	return tree
+}
+
+func ascendAll(t *testing.T, tree *BPlusTree[int]) []int {
+	t.Helper()
+	var keys []int
+	if err := tree.Ascend(func(key int) bool {
+		keys = append(keys, key)
+		return true
+	}); err != nil {
+		t.Fatalf("Ascend() error = %v", err)
+	}
+	return keys
+}
+
+func TestBPlusTreeMatchesSortedSlice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index")
+	opts := BPlusOptions{PageSize: 128, PoolSize: 4}
+	tree := openInts(t, path, opts)
+	rng := rand.New(rand.NewSource(23))
+	var want []int
+
+	for i := 0; i < 5000; i++ {
+		value := rng.Intn(500)
+		if rng.Intn(3) == 0 {
+			j := sort.SearchInts(want, value)
+			found := j < len(want) && want[j] == value
+			if found {
+				want = append(want[:j], want[j+1:]...)
+			}
+			if got, err := tree.Delete(value); err != nil || got != found {
+				t.Fatalf("Delete(%d) = %v, %v, want %v", value, got, err, found)
+			}
+		} else {
+			j := sort.SearchInts(want, value+1)
+			want = insertAt(want, j, value)
+			if err := tree.Insert(value); err != nil {
+				t.Fatalf("Insert(%d) error = %v", value, err)
+			}
+		}
+
+		if i%1000 == 999 {
+			// Reopening reads every node back from the file.
+			if err := tree.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			tree = openInts(t, path, opts)
+		}
+		if i%100 == 0 {
+			if got := ascendAll(t, tree); !reflect.DeepEqual(got, want) {
+				t.Fatalf("after step %d, Ascend() = %v, want %v", i, got, want)
+			}
+		}
+	}
+
+	if tree.Len() != len(want) {
+		t.Errorf("Len() = %d, want %d", tree.Len(), len(want))
+	}
+	for value := -1; value <= 500; value++ {
+		j := sort.SearchInts(want, value)
+		wantFound := j < len(want) && want[j] == value
+		if got, found, err := tree.Get(value); err != nil || found != wantFound || (found && got != value) {
+			t.Errorf("Get(%d) = %v, %v, %v, want found %v", value, got, found, err, wantFound)
+		}
+	}
+	if err := tree.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestBPlusTreeInsertWriteBackFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index")
+	opts := BPlusOptions{PageSize: 128, PoolSize: 4}
+	tree := openInts(t, path, opts)
+	var want []int
+	for i := 0; i < 200; i += 2 {
+		if err := tree.Insert(i); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, i)
+	}
+	if err := tree.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pages can still be read, but writing back an evicted page fails.
+	readOnly, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree.pool.file = readOnly
+
+	rng := rand.New(rand.NewSource(23))
+	failures := 0
+	for i := 0; i < 300; i++ {
+		value := rng.Intn(400)
+		count, pages, root := tree.Len(), tree.meta.pages, tree.meta.root
+		if err := tree.Insert(value); err != nil {
+			failures++
+			if tree.Len() != count || tree.meta.pages != pages || tree.meta.root != root {
+				t.Fatalf("failed Insert(%d) changed Len() from %d to %d, pages from %d to %d and root from %d to %d",
+					value, count, tree.Len(), pages, tree.meta.pages, root, tree.meta.root)
+			}
+		} else {
+			want = insertAt(want, sort.SearchInts(want, value+1), value)
+		}
+
+		for id, node := range tree.pool.nodes {
+			if node.pins != 0 {
+				t.Fatalf("after Insert(%d), page %d has %d pin(s) left", value, id, node.pins)
+			}
+			if node.size > int(tree.meta.pageSize) {
+				t.Fatalf("after Insert(%d), page %d needs %d bytes of %d", value, id, node.size, tree.meta.pageSize)
+			}
+		}
+	}
+	if failures == 0 {
+		t.Fatal("no Insert() failed to write back a page")
+	}
+
+	readOnly.Close()
+	tree.pool.file = tree.file
+	if err := tree.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	tree = openInts(t, path, opts)
+	defer tree.Close()
+	if got := ascendAll(t, tree); !reflect.DeepEqual(got, want) || tree.Len() != len(want) {
+		t.Errorf("after reopening, Ascend() = %v with Len() = %d, want %v", got, tree.Len(), want)
+	}
+}
+
+func TestBPlusTreeLargePages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index")
+	opts := BPlusOptions{PageSize: 1 << 20}
+	tree := openInts(t, path, opts)
+
+	// A page of this size holds more small keys than a 16-bit count could record.
+	const n = 70000
+	for i := 0; i < n; i++ {
+		if err := tree.Insert(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tree.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tree = openInts(t, path, opts)
+	defer tree.Close()
+	if got := len(ascendAll(t, tree)); got != n || tree.Len() != n {
+		t.Errorf("after reopening, Ascend() visited %d key(s) with Len() = %d, want %d", got, tree.Len(), n)
+	}
+}
+
+func TestBPlusTreeRange(t *testing.T) {
+	tree := openInts(t, filepath.Join(t.TempDir(), "index"), BPlusOptions{PageSize: 128, PoolSize: 8})
+	defer tree.Close()
+	for i := 0; i < 300; i++ {
+		if err := tree.Insert(i / 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		lo, hi      int
+		inclusivity Inclusivity
+		want        []int
+	}{
+		{
+			name:        "It includes duplicates of both bounds",
+			lo:          40,
+			hi:          41,
+			inclusivity: IncludeBoth,
+			want:        []int{40, 40, 40, 41, 41, 41},
+		},
+		{
+			name:        "It excludes both bounds",
+			lo:          40,
+			hi:          42,
+			inclusivity: ExcludeBoth,
+			want:        []int{41, 41, 41},
+		},
+		{
+			name:        "It includes only the high bound",
+			lo:          98,
+			hi:          99,
+			inclusivity: IncludeHigh,
+			want:        []int{99, 99, 99},
+		},
+		{
+			name:        "It returns nothing past the largest key",
+			lo:          100,
+			hi:          200,
+			inclusivity: IncludeBoth,
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tree.Range(tt.lo, tt.hi, tt.inclusivity)
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Range(%d, %d) = %v, %v, want %v", tt.lo, tt.hi, got, err, tt.want)
+			}
+		})
+	}
+
+	var got []int
+	if err := tree.AscendRange(10, 90, func(key int) bool {
+		got = append(got, key)
+		return len(got) < 5
+	}); err != nil || !reflect.DeepEqual(got, []int{10, 10, 10, 11, 11}) {
+		t.Errorf("AscendRange() visited %v, %v, want it to stop after 5 keys", got, err)
+	}
+}
+
+func TestBPlusTreePolicies(t *testing.T) {
+	dir := t.TempDir()
+	records := func(name string, policy DuplicatePolicy) *BPlusTree[string] {
+		tree, err := OpenBPlusTree[string](filepath.Join(dir, name), func(a, b string) int {
+			return strings.Compare(a[:1], b[:1])
+		}, StringCodec{}, BPlusOptions{Duplicates: policy})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tree
+	}
+
+	reject := records("reject", RejectDuplicates)
+	defer reject.Close()
+	if err := reject.Insert("a1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reject.Insert("a2"); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Insert() error = %v, want %v", err, ErrDuplicate)
+	}
+
+	replace := records("replace", ReplaceDuplicates)
+	defer replace.Close()
+	for _, key := range []string{"a1", "b1", "a2"} {
+		if err := replace.Insert(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, _, _ := replace.Get("a"); got != "a2" || replace.Len() != 2 {
+		t.Errorf("Get() = %q with Len() = %d, want %q with 2", got, replace.Len(), "a2")
+	}
+}
+
+func TestBPlusTreeErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tree := openInts(t, filepath.Join(dir, "index"), BPlusOptions{})
+	if err := tree.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Insert(1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Insert() after Close() error = %v, want %v", err, ErrClosed)
+	}
+	if err := tree.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Close() twice error = %v, want %v", err, ErrClosed)
+	}
+
+	strs, err := OpenBPlusTree[string](filepath.Join(dir, "strings"), strings.Compare, StringCodec{}, BPlusOptions{PageSize: 128})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer strs.Close()
+	if err := strs.Insert(strings.Repeat("x", 64)); !errors.Is(err, ErrKeyTooLarge) {
+		t.Errorf("Insert() of a large key error = %v, want %v", err, ErrKeyTooLarge)
+	}
+
+	garbage := filepath.Join(dir, "garbage")
+	if err := os.WriteFile(garbage, []byte("not a B+ tree at all, but long enough"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenBPlusTree[int](garbage, intComparator, IntCodec{}, BPlusOptions{}); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("OpenBPlusTree() of a corrupt file error = %v, want %v", err, ErrInvalidFormat)
+	}
+}
+
+func TestOpenBPlusTreeRejectsCorruptMeta(t *testing.T) {
+	tests := []struct {
+		name string
+		meta bplusMeta
+	}{
+		{
+			name: "It rejects a page size of 0",
+			meta: bplusMeta{pageSize: 0, root: 1, pages: 2},
+		},
+		{
+			name: "It rejects a page size above the largest supported",
+			meta: bplusMeta{pageSize: bplusMaxPageSize + 1, root: 1, pages: 2},
+		},
+		{
+			name: "It rejects the meta page as the root",
+			meta: bplusMeta{pageSize: 4096, root: 0, pages: 2},
+		},
+		{
+			name: "It rejects a root past the last page",
+			meta: bplusMeta{pageSize: 4096, root: 2, pages: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := bplusMeta{pageSize: bplusMinPageSize}.encode()
+			binary.BigEndian.PutUint32(page[6:], tt.meta.pageSize)
+			binary.BigEndian.PutUint64(page[10:], uint64(tt.meta.root))
+			binary.BigEndian.PutUint64(page[18:], tt.meta.pages)
+
+			path := filepath.Join(t.TempDir(), "index")
+			if err := os.WriteFile(path, page, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := OpenBPlusTree[int](path, intComparator, IntCodec{}, BPlusOptions{}); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("OpenBPlusTree() error = %v, want %v", err, ErrInvalidFormat)
+			}
+		})
+	}
+}
+
+func BenchmarkBPlusTreeInsert(b *testing.B) {
+	tree, err := OpenBPlusTree[int](filepath.Join(b.TempDir(), "index"), intComparator, IntCodec{}, BPlusOptions{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer tree.Close()
+	rng := rand.New(rand.NewSource(1))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := tree.Insert(rng.Int()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package trees
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentTree is a two-three tree that is safe to share between goroutines.
+// Writers are serialised by a mutex, and publish each update as a new version of a PersistentTree.
+// Readers never take a lock: they load the current version atomically and read it, so they see
+// every write that completed before they started, and are never blocked by writers in progress.
+// The zero value is not usable; call NewConcurrentTree.
+type ConcurrentTree[T any] struct {
+	// mu serialises writers.
+	mu sync.Mutex
+
+	// current holds the latest *PersistentTree[T].
+	current atomic.Value
+}
+
+// NewConcurrentTree is a constructor for an empty concurrent two-three tree ordered by the given comparator.
+// The tree allows duplicate values.
+func NewConcurrentTree[T any](comparator func(a, b T) int) *ConcurrentTree[T] {
+	return NewConcurrentTreeWithPolicy(comparator, AllowDuplicates)
+}
+
+// NewConcurrentTreeWithPolicy is a constructor for an empty concurrent two-three tree ordered by the given comparator,
+// which handles duplicate values according to the given policy.
+func NewConcurrentTreeWithPolicy[T any](comparator func(a, b T) int, duplicates DuplicatePolicy) *ConcurrentTree[T] {
+	tree := &ConcurrentTree[T]{}
+	tree.current.Store(NewPersistentTreeWithPolicy(comparator, duplicates))
+	return tree
+}
+
+// Snapshot returns the current version of the tree.
+// The snapshot is immutable, so it can be read at leisure without observing later writes.
+func (tree *ConcurrentTree[T]) Snapshot() *PersistentTree[T] {
+	return tree.current.Load().(*PersistentTree[T])
+}
+
+// Insert inserts a value into the tree.
+// A value equal to one already in the tree is handled according to the tree's DuplicatePolicy.
+func (tree *ConcurrentTree[T]) Insert(value T) error {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+
+	next, err := tree.Snapshot().Insert(value)
+	if err != nil {
+		return err
+	}
+	tree.current.Store(next)
+	return nil
+}
+
+// Delete removes a value from the tree.
+// It returns whether the value was found.
+func (tree *ConcurrentTree[T]) Delete(value T) bool {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+
+	next, found := tree.Snapshot().Delete(value)
+	if found {
+		tree.current.Store(next)
+	}
+	return found
+}
+
+// Get finds a value in the tree.
+// It returns the stored value equal to the given one, and whether it was found.
+func (tree *ConcurrentTree[T]) Get(value T) (T, bool) {
+	return tree.Snapshot().Get(value)
+}
+
+// Contains reports whether a value equal to the given one is stored in the tree.
+func (tree *ConcurrentTree[T]) Contains(value T) bool {
+	return tree.Snapshot().Contains(value)
+}
+
+// Len returns the number of values stored in the tree.
+func (tree *ConcurrentTree[T]) Len() int {
+	return tree.Snapshot().Len()
+}
+
+// AscendRange calls fn, in ascending order, for each value of the tree in the half-open range [lo, hi).
+// The values visited all come from the version of the tree current when AscendRange was called.
+// Iteration stops early if fn returns false.
+func (tree *ConcurrentTree[T]) AscendRange(lo, hi T, fn func(T) bool) {
+	tree.Snapshot().AscendRange(lo, hi, fn)
+}
+
+// Range finds the values of the tree between lo and hi.
+// The inclusivity determines whether values equal to lo or hi are part of the range.
+// It returns the values in ascending order, all from the version of the tree current when Range was called.
+func (tree *ConcurrentTree[T]) Range(lo, hi T, inclusivity Inclusivity) []T {
+	return tree.Snapshot().Range(lo, hi, inclusivity)
+}
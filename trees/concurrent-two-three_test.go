@@ -0,0 +1,109 @@
+package trees
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentTree(t *testing.T) {
+	tree := NewConcurrentTreeWithPolicy(intComparator, RejectDuplicates)
+	for _, value := range []int{5, 1, 9, 3, 7} {
+		if err := tree.Insert(value); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+
+	if err := tree.Insert(3); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Insert() error = %v, want ErrDuplicate", err)
+	}
+	if got, ok := tree.Get(7); !ok || got != 7 {
+		t.Errorf("Get() = %v, %v, want 7, true", got, ok)
+	}
+
+	snapshot := tree.Snapshot()
+	if !tree.Delete(5) || tree.Delete(5) {
+		t.Errorf("Delete() did not find the value exactly once")
+	}
+	if tree.Contains(5) || !snapshot.Contains(5) {
+		t.Errorf("Delete() changed an earlier snapshot")
+	}
+
+	if got := tree.Range(1, 9, ExcludeBoth); !reflect.DeepEqual(got, []int{3, 7}) {
+		t.Errorf("Range() = %v, want [3 7]", got)
+	}
+	if tree.Len() != 4 {
+		t.Errorf("Len() = %v, want 4", tree.Len())
+	}
+}
+
+func TestConcurrentTreeStress(t *testing.T) {
+	const writers, readers, perWriter = 4, 4, 500
+
+	tree := NewConcurrentTree(intComparator)
+	var writersDone sync.WaitGroup
+	var readersDone sync.WaitGroup
+	stop := make(chan struct{})
+
+	for w := 0; w < writers; w++ {
+		writersDone.Add(1)
+		go func(w int) {
+			defer writersDone.Done()
+			for i := 0; i < perWriter; i++ {
+				value := i*writers + w
+				if err := tree.Insert(value); err != nil {
+					t.Errorf("Insert() error = %v", err)
+				}
+				// Every even value is removed again, so deletes interleave with inserts.
+				if value%2 == 0 && !tree.Delete(value) {
+					t.Errorf("Delete(%d) did not find the value", value)
+				}
+			}
+		}(w)
+	}
+
+	for r := 0; r < readers; r++ {
+		readersDone.Add(1)
+		go func() {
+			defer readersDone.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				prev := -1
+				tree.AscendRange(0, writers*perWriter, func(value int) bool {
+					if value <= prev {
+						t.Errorf("AscendRange() visited %d after %d", value, prev)
+					}
+					prev = value
+					return true
+				})
+				tree.Get(prev)
+
+				snapshot := tree.Snapshot()
+				if got := len(snapshot.InOrder()); got != snapshot.Len() {
+					t.Errorf("snapshot holds %d values, but Len is %d", got, snapshot.Len())
+				}
+			}
+		}()
+	}
+
+	writersDone.Wait()
+	close(stop)
+	readersDone.Wait()
+
+	want := writers * perWriter / 2
+	if got := tree.Len(); got != want {
+		t.Errorf("Len() = %v, want %v", got, want)
+	}
+	for i, value := range tree.Snapshot().InOrder() {
+		if value != 2*i+1 {
+			t.Fatalf("tree holds %d at index %d, want %d", value, i, 2*i+1)
+		}
+	}
+	checkPersistentShape(t, tree.Snapshot().root, intComparator)
+}
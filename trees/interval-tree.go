@@ -0,0 +1,145 @@
+package trees
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidInterval is returned when inserting an interval whose end is not after its start.
+var ErrInvalidInterval = errors.New("interval end must be after its start")
+
+// Interval is a half-open interval [Start, End) of keys, carrying a value.
+type Interval[K, V any] struct {
+	Start, End K
+	Value      V
+}
+
+// maxEnd is the summary of a subtree of an IntervalTree: the latest end of its intervals, if it has any.
+type maxEnd[K any] struct {
+	end K
+	ok  bool
+}
+
+// IntervalTree is a two-three tree of half-open intervals, ordered by start and then end, in which
+// every subtree records the latest end of its intervals. A query skips every subtree that ends
+// before the queried range, and stops at the first interval that starts after it.
+// The zero value is not usable; call NewIntervalTree.
+type IntervalTree[K, V any] struct {
+	tree *AugmentedTree[Interval[K, V], maxEnd[K]]
+
+	// compare is used to compare two keys.
+	// returns -1 if a < b, 0 if a == b, 1 if a > b
+	compare func(K, K) int
+}
+
+// NewIntervalTree is a constructor for an empty interval tree whose keys are ordered by the given comparator.
+// The tree may hold several intervals with the same bounds.
+func NewIntervalTree[K, V any](compare func(a, b K) int) *IntervalTree[K, V] {
+	comparator := func(a, b Interval[K, V]) int {
+		if c := compare(a.Start, b.Start); c != 0 {
+			return c
+		}
+		return compare(a.End, b.End)
+	}
+	monoid := Monoid[Interval[K, V], maxEnd[K]]{
+		Identity: maxEnd[K]{},
+		Combine: func(a, b maxEnd[K]) maxEnd[K] {
+			if !a.ok || (b.ok && compare(b.end, a.end) > 0) {
+				return b
+			}
+			return a
+		},
+		Measure: func(interval Interval[K, V]) maxEnd[K] {
+			return maxEnd[K]{end: interval.End, ok: true}
+		},
+	}
+
+	return &IntervalTree[K, V]{
+		tree:    NewAugmentedTree(comparator, monoid),
+		compare: compare,
+	}
+}
+
+// Len returns the number of intervals stored in the tree.
+func (tree *IntervalTree[K, V]) Len() int {
+	return tree.tree.Len()
+}
+
+// Insert adds the interval [start, end) with its value to the tree.
+// It returns an error wrapping ErrInvalidInterval if end is not after start.
+func (tree *IntervalTree[K, V]) Insert(start, end K, value V) error {
+	if tree.compare(start, end) >= 0 {
+		return fmt.Errorf("%w: [%v, %v)", ErrInvalidInterval, start, end)
+	}
+	return tree.tree.Insert(Interval[K, V]{Start: start, End: end, Value: value})
+}
+
+// Delete removes an interval [start, end) from the tree. If several intervals have these bounds, one of them is removed.
+// It returns whether such an interval was found.
+func (tree *IntervalTree[K, V]) Delete(start, end K) (bool, error) {
+	return tree.tree.Delete(Interval[K, V]{Start: start, End: end})
+}
+
+// search calls fn, in order, for each interval of the subtree that ends after a and whose start satisfies before.
+// Subtrees that end no later than a are skipped.
+// It returns false once fn returns false or an interval that starts too late is reached.
+func (tree *IntervalTree[K, V]) search(node *TwoThreeNode[augmentedValue[Interval[K, V], maxEnd[K]]], a K, before func(start K) bool, fn func(Interval[K, V]) bool) bool {
+	if node == nil {
+		return true
+	}
+	if summary := tree.tree.summaryOf(node); !summary.ok || tree.compare(summary.end, a) <= 0 {
+		return true
+	}
+
+	children := nodeChildren(node)
+	data := nodeData(node)
+	for i := 0; i <= len(data); i++ {
+		if i < len(children) && !tree.search(children[i], a, before, fn) {
+			return false
+		}
+
+		if i == len(data) {
+			break
+		}
+		// Every later interval starts no earlier than this one.
+		if !before(data[i].value.Start) {
+			return false
+		}
+		if tree.compare(data[i].value.End, a) > 0 && !fn(data[i].value) {
+			return false
+		}
+	}
+	return true
+}
+
+// AscendOverlapping calls fn, in order of start, for each interval of the tree that overlaps [a, b).
+// Iteration stops early if fn returns false.
+func (tree *IntervalTree[K, V]) AscendOverlapping(a, b K, fn func(Interval[K, V]) bool) {
+	tree.search(tree.tree.root(), a, func(start K) bool {
+		return tree.compare(start, b) < 0
+	}, fn)
+}
+
+// Overlapping finds the intervals of the tree that overlap [a, b), i.e. that start before b and end after a.
+// It returns the intervals in order of start.
+func (tree *IntervalTree[K, V]) Overlapping(a, b K) []Interval[K, V] {
+	var result []Interval[K, V]
+	tree.AscendOverlapping(a, b, func(interval Interval[K, V]) bool {
+		result = append(result, interval)
+		return true
+	})
+	return result
+}
+
+// Stab finds the intervals of the tree that contain x, i.e. that start at or before x and end after it.
+// It returns the intervals in order of start.
+func (tree *IntervalTree[K, V]) Stab(x K) []Interval[K, V] {
+	var result []Interval[K, V]
+	tree.search(tree.tree.root(), x, func(start K) bool {
+		return tree.compare(start, x) <= 0
+	}, func(interval Interval[K, V]) bool {
+		result = append(result, interval)
+		return true
+	})
+	return result
+}
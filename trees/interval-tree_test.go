@@ -0,0 +1,186 @@
+package trees
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestIntervalTree(t *testing.T) {
+	/*
+		0    2    4    6    8    10   12
+		[---room A---)
+		     [-room B-)
+		               [----room C----)
+		          [room D)
+	*/
+	tree := NewIntervalTree[int, string](intComparator)
+	for _, interval := range []Interval[int, string]{
+		{0, 6, "A"}, {2, 5, "B"}, {7, 12, "C"}, {4, 7, "D"},
+	} {
+		if err := tree.Insert(interval.Start, interval.End, interval.Value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	values := func(intervals []Interval[int, string]) []string {
+		var result []string
+		for _, interval := range intervals {
+			result = append(result, interval.Value)
+		}
+		return result
+	}
+
+	stabs := []struct {
+		name string
+		x    int
+		want []string
+	}{
+		{name: "It finds every interval containing the point", x: 4, want: []string{"A", "B", "D"}},
+		{name: "It includes intervals starting at the point", x: 7, want: []string{"C"}},
+		{name: "It excludes intervals ending at the point", x: 6, want: []string{"D"}},
+		{name: "It finds nothing past the last interval", x: 12, want: nil},
+	}
+	for _, tt := range stabs {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := values(tree.Stab(tt.x)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Stab(%d) = %v, want %v", tt.x, got, tt.want)
+			}
+		})
+	}
+
+	overlaps := []struct {
+		name string
+		a, b int
+		want []string
+	}{
+		{name: "It finds every interval overlapping the range", a: 5, b: 8, want: []string{"A", "D", "C"}},
+		{name: "It excludes intervals that only touch the range", a: 12, b: 14, want: nil},
+		{name: "It excludes intervals starting at the end of the range", a: 6, b: 7, want: []string{"D"}},
+	}
+	for _, tt := range overlaps {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := values(tree.Overlapping(tt.a, tt.b)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Overlapping(%d, %d) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+
+	for i, want := range []bool{true, false} {
+		if found, err := tree.Delete(4, 7); found != want || err != nil {
+			t.Errorf("Delete(4, 7) call %d = %v, %v, want %v, nil", i+1, found, err, want)
+		}
+	}
+	if got := values(tree.Stab(6)); got != nil {
+		t.Errorf("Stab(6) after Delete() = %v, want none", got)
+	}
+}
+
+func TestIntervalTreeRejectsEmptyIntervals(t *testing.T) {
+	tree := NewIntervalTree[int, struct{}](intComparator)
+	for _, bounds := range [][2]int{{3, 3}, {5, 2}} {
+		if err := tree.Insert(bounds[0], bounds[1], struct{}{}); !errors.Is(err, ErrInvalidInterval) {
+			t.Errorf("Insert(%d, %d) error = %v, want %v", bounds[0], bounds[1], err, ErrInvalidInterval)
+		}
+	}
+	if tree.Len() != 0 {
+		t.Errorf("Len() = %v, want 0", tree.Len())
+	}
+}
+
+func TestIntervalTreeRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(22))
+	tree := NewIntervalTree[int, int](intComparator)
+	var intervals []Interval[int, int]
+
+	for i := 0; i < 1500; i++ {
+		if len(intervals) > 0 && rng.Intn(4) == 0 {
+			j := rng.Intn(len(intervals))
+			if found, err := tree.Delete(intervals[j].Start, intervals[j].End); !found || err != nil {
+				t.Fatalf("Delete(%d, %d) = %v, %v, want true, nil", intervals[j].Start, intervals[j].End, found, err)
+			}
+			intervals = append(intervals[:j], intervals[j+1:]...)
+			continue
+		}
+		start := rng.Intn(1000)
+		interval := Interval[int, int]{Start: start, End: start + 1 + rng.Intn(50), Value: i}
+		if err := tree.Insert(interval.Start, interval.End, interval.Value); err != nil {
+			t.Fatal(err)
+		}
+		intervals = append(intervals, interval)
+	}
+
+	bounds := func(list []Interval[int, int]) map[[2]int]int {
+		counts := map[[2]int]int{}
+		for _, interval := range list {
+			counts[[2]int{interval.Start, interval.End}]++
+		}
+		return counts
+	}
+
+	for q := 0; q < 200; q++ {
+		a := rng.Intn(1100) - 50
+		b := a + 1 + rng.Intn(40)
+
+		var wantOverlap, wantStab []Interval[int, int]
+		for _, interval := range intervals {
+			if interval.Start < b && interval.End > a {
+				wantOverlap = append(wantOverlap, interval)
+			}
+			if interval.Start <= a && interval.End > a {
+				wantStab = append(wantStab, interval)
+			}
+		}
+
+		if got := tree.Overlapping(a, b); !reflect.DeepEqual(bounds(got), bounds(wantOverlap)) {
+			t.Errorf("Overlapping(%d, %d) = %v, want %v", a, b, got, wantOverlap)
+		}
+		if got := tree.Stab(a); !reflect.DeepEqual(bounds(got), bounds(wantStab)) {
+			t.Errorf("Stab(%d) = %v, want %v", a, got, wantStab)
+		}
+	}
+	if err := tree.tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIntervalTreeSkipsDistantSubtrees(t *testing.T) {
+	comparisons := 0
+	counting := func(a, b int) int {
+		comparisons++
+		return intComparator(a, b)
+	}
+	tree := NewIntervalTree[int, struct{}](counting)
+	for i := 0; i < 100000; i++ {
+		if err := tree.Insert(10*i, 10*i+5, struct{}{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	comparisons = 0
+	if got := tree.Stab(500002); len(got) != 1 {
+		t.Fatalf("Stab() = %v, want one interval", got)
+	}
+	if limit := 20 * tree.tree.Height(); comparisons > limit {
+		t.Errorf("Stab() made %d comparisons, want at most %d", comparisons, limit)
+	}
+}
+
+func TestIntervalTreeStopsEarly(t *testing.T) {
+	tree := NewIntervalTree[int, int](intComparator)
+	for i := 0; i < 10; i++ {
+		if err := tree.Insert(i, 100, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []int
+	tree.AscendOverlapping(50, 60, func(interval Interval[int, int]) bool {
+		got = append(got, interval.Value)
+		return len(got) < 3
+	})
+	if !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("AscendOverlapping() = %v, want [0 1 2]", got)
+	}
+}
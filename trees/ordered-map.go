@@ -0,0 +1,114 @@
+package trees
+
+// entry is a key/value pair stored in an OrderedMap.
+type entry[K, V any] struct {
+	key   K
+	value V
+}
+
+// OrderedMap is a map whose entries are kept sorted by key in a two-three tree.
+// The zero value is not usable; call NewOrderedMap.
+type OrderedMap[K, V any] struct {
+	tree *TwoThreeTree[entry[K, V]]
+}
+
+// NewOrderedMap is a constructor for an empty ordered map whose keys are ordered by the given comparator.
+func NewOrderedMap[K, V any](comparator func(a, b K) int) *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{
+		tree: NewTwoThreeTree(func(a, b entry[K, V]) int {
+			return comparator(a.key, b.key)
+		}),
+	}
+}
+
+// lookup finds the entry stored for the given key.
+// It returns a pointer to the entry, or nil if the key is not in the map.
+func (m *OrderedMap[K, V]) lookup(key K) (*entry[K, V], error) {
+	if m.tree.root == nil {
+		return nil, nil
+	}
+
+	node, idx, err := findNode(m.tree.root, entry[K, V]{key: key})
+	if err != nil || node == nil {
+		return nil, err
+	}
+	return nodeData(node)[idx], nil
+}
+
+// Put stores the value for the given key, replacing any value already stored for it.
+func (m *OrderedMap[K, V]) Put(key K, value V) error {
+	return m.Upsert(key, func(V, bool) V {
+		return value
+	})
+}
+
+// Upsert stores the value returned by fn for the given key.
+// fn is called with the value currently stored for the key, and whether the key exists.
+func (m *OrderedMap[K, V]) Upsert(key K, fn func(old V, exists bool) V) error {
+	existing, err := m.lookup(key)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		existing.value = fn(existing.value, true)
+		return nil
+	}
+
+	var zeroVal V
+	return m.tree.Insert(entry[K, V]{key: key, value: fn(zeroVal, false)})
+}
+
+// Get finds the value stored for the given key.
+// It returns the value, and whether the key exists.
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	var zeroVal V
+	existing, _ := m.lookup(key)
+	if existing == nil {
+		return zeroVal, false
+	}
+	return existing.value, true
+}
+
+// Delete removes the given key and its value from the map.
+// It returns whether the key existed.
+func (m *OrderedMap[K, V]) Delete(key K) (bool, error) {
+	return m.tree.Delete(entry[K, V]{key: key})
+}
+
+// Len returns the number of keys in the map.
+func (m *OrderedMap[K, V]) Len() int {
+	return m.tree.Len()
+}
+
+// Ascend calls fn for each key/value pair of the map, in ascending key order.
+// Iteration stops early if fn returns false.
+func (m *OrderedMap[K, V]) Ascend(fn func(key K, value V) bool) {
+	cursor := m.tree.Cursor()
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		e := cursor.Value()
+		if !fn(e.key, e.value) {
+			return
+		}
+	}
+}
+
+// Keys returns the keys of the map in ascending order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	var keys []K
+	m.Ascend(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns the values of the map, in ascending order of their keys.
+func (m *OrderedMap[K, V]) Values() []V {
+	var values []V
+	m.Ascend(func(_ K, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
@@ -0,0 +1,78 @@
+package trees
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[string, int](stringComparator)
+
+	for i, key := range []string{"delta", "alpha", "echo", "charlie", "bravo"} {
+		if err := m.Put(key, i); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	if got := m.Len(); got != 5 {
+		t.Errorf("Len() = %v, want 5", got)
+	}
+	if got, ok := m.Get("charlie"); !ok || got != 3 {
+		t.Errorf("Get() = %v, %v, want 3, true", got, ok)
+	}
+	if _, ok := m.Get("foxtrot"); ok {
+		t.Errorf("Get() found a missing key")
+	}
+
+	if err := m.Put("charlie", 30); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if got, _ := m.Get("charlie"); got != 30 {
+		t.Errorf("Put() did not replace the value: Get() = %v, want 30", got)
+	}
+	if got := m.Len(); got != 5 {
+		t.Errorf("Put() of an existing key changed Len() to %v, want 5", got)
+	}
+
+	if want := []string{"alpha", "bravo", "charlie", "delta", "echo"}; !reflect.DeepEqual(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+	if want := []int{1, 4, 30, 0, 2}; !reflect.DeepEqual(m.Values(), want) {
+		t.Errorf("Values() = %v, want %v", m.Values(), want)
+	}
+
+	if found, err := m.Delete("alpha"); !found || err != nil {
+		t.Errorf("Delete() = %v, %v, want true, nil", found, err)
+	}
+	if found, _ := m.Delete("alpha"); found {
+		t.Errorf("Delete() found a deleted key")
+	}
+	if got := m.Len(); got != 4 {
+		t.Errorf("Len() = %v, want 4", got)
+	}
+}
+
+func TestOrderedMapUpsert(t *testing.T) {
+	m := NewOrderedMap[string, int](stringComparator)
+	increment := func(old int, exists bool) int {
+		if !exists {
+			return 1
+		}
+		return old + 1
+	}
+
+	for _, word := range []string{"a", "b", "a", "c", "a", "b"} {
+		if err := m.Upsert(word, increment); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+	}
+
+	got := map[string]int{}
+	m.Ascend(func(key string, value int) bool {
+		got[key] = value
+		return true
+	})
+	if want := map[string]int{"a": 3, "b": 2, "c": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Upsert() counts = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,456 @@
+package trees
+
+/* PersistentTree is an immutable two-three tree.
+Every update copies only the nodes on the path it changes and returns a new tree that
+shares all other nodes with the old one, so older versions stay valid and a snapshot
+is just a reference to a tree.
+*/
+
+// persistentNode is a node of a PersistentTree. It is never modified once built, so it
+// has no parent pointer and may be shared between many versions of a tree.
+type persistentNode[T any] struct {
+	// A node has one or two data values, except while a deletion is repairing it.
+	data []T
+
+	// A node has either no children, or one more child than data values.
+	children []*persistentNode[T]
+
+	// the height of the subtree rooted at this node.
+	height int
+
+	// the number of values in the subtree rooted at this node.
+	size int
+}
+
+// newPersistentNode builds a node from the given data and children, calculating its height and size.
+func newPersistentNode[T any](data []T, children []*persistentNode[T]) *persistentNode[T] {
+	node := &persistentNode[T]{
+		data:     data,
+		children: children,
+		height:   1,
+		size:     len(data),
+	}
+	if len(children) > 0 {
+		node.height = children[0].height + 1
+	}
+	for _, child := range children {
+		node.size += child.size
+	}
+	return node
+}
+
+// copyPersistentNode returns a new node with copies of the data and children of node,
+// so that the copy may be modified while node is still shared.
+func copyPersistentNode[T any](node *persistentNode[T]) *persistentNode[T] {
+	return newPersistentNode(
+		append([]T(nil), node.data...),
+		append([]*persistentNode[T](nil), node.children...),
+	)
+}
+
+// PersistentTree is an immutable two-three tree whose updates return new versions of the tree.
+// The zero value is not usable; call NewPersistentTree.
+type PersistentTree[T any] struct {
+	// root is nil while the tree is empty.
+	root *persistentNode[T]
+
+	// comparator is used to compare two values.
+	// returns -1 if a < b, 0 if a == b, 1 if a > b
+	comparator func(T, T) int
+
+	// duplicates decides what Insert does with a value equal to one already in the tree.
+	duplicates DuplicatePolicy
+}
+
+// NewPersistentTree is a constructor for an empty persistent two-three tree ordered by the given comparator.
+// The tree allows duplicate values.
+func NewPersistentTree[T any](comparator func(a, b T) int) *PersistentTree[T] {
+	return NewPersistentTreeWithPolicy(comparator, AllowDuplicates)
+}
+
+// NewPersistentTreeWithPolicy is a constructor for an empty persistent two-three tree ordered by the given comparator,
+// which handles duplicate values according to the given policy.
+func NewPersistentTreeWithPolicy[T any](comparator func(a, b T) int, duplicates DuplicatePolicy) *PersistentTree[T] {
+	return &PersistentTree[T]{
+		root:       nil,
+		comparator: comparator,
+		duplicates: duplicates,
+	}
+}
+
+// withRoot returns a new version of the tree with the given root.
+func (tree *PersistentTree[T]) withRoot(root *persistentNode[T]) *PersistentTree[T] {
+	return &PersistentTree[T]{
+		root:       root,
+		comparator: tree.comparator,
+		duplicates: tree.duplicates,
+	}
+}
+
+// find locates the value in the tree.
+// It returns the path of nodes from the root to the node holding the value, and the index of the value within it.
+// The path is nil if the value is not in the tree.
+func (tree *PersistentTree[T]) find(value T) ([]*persistentNode[T], int) {
+	var path []*persistentNode[T]
+	node := tree.root
+	for node != nil {
+		path = append(path, node)
+		next := len(node.data)
+		for i, datum := range node.data {
+			c := tree.comparator(value, datum)
+			if c == 0 {
+				return path, i
+			}
+			if c < 0 {
+				next = i
+				break
+			}
+		}
+		if len(node.children) == 0 {
+			break
+		}
+		node = node.children[next]
+	}
+	return nil, 0
+}
+
+// persistentSplit is the result of inserting into a subtree: either a replacement node,
+// or, when the node overflowed, two nodes and the value that separates them.
+type persistentSplit[T any] struct {
+	node        *persistentNode[T]
+	left, right *persistentNode[T]
+	mid         T
+}
+
+// settlePersistent builds a node from the data and children, splitting it around its middle value when it has three.
+func settlePersistent[T any](data []T, children []*persistentNode[T]) persistentSplit[T] {
+	if len(data) < 3 {
+		return persistentSplit[T]{node: newPersistentNode(data, children)}
+	}
+
+	var leftChildren, rightChildren []*persistentNode[T]
+	if len(children) > 0 {
+		leftChildren, rightChildren = children[:2:2], children[2:]
+	}
+	return persistentSplit[T]{
+		left:  newPersistentNode(data[:1:1], leftChildren),
+		mid:   data[1],
+		right: newPersistentNode(data[2:], rightChildren),
+	}
+}
+
+// insert inserts the value into the subtree, copying the nodes along the path it descends.
+// Duplicates are placed after the values equal to them.
+func (tree *PersistentTree[T]) insert(node *persistentNode[T], value T) persistentSplit[T] {
+	pos := len(node.data)
+	for i, datum := range node.data {
+		if tree.comparator(value, datum) < 0 {
+			pos = i
+			break
+		}
+	}
+
+	data := append([]T(nil), node.data...)
+	if len(node.children) == 0 {
+		return settlePersistent(insertAt(data, pos, value), nil)
+	}
+
+	children := append([]*persistentNode[T](nil), node.children...)
+	result := tree.insert(node.children[pos], value)
+	if result.node != nil {
+		children[pos] = result.node
+		return persistentSplit[T]{node: newPersistentNode(data, children)}
+	}
+
+	children[pos] = result.left
+	return settlePersistent(insertAt(data, pos, result.mid), insertAt(children, pos+1, result.right))
+}
+
+// replace returns a copy of the path to the node holding a value, with the value replaced.
+func (tree *PersistentTree[T]) replace(path []*persistentNode[T], idx int, value T) *persistentNode[T] {
+	replaced := copyPersistentNode(path[len(path)-1])
+	replaced.data[idx] = value
+	for i := len(path) - 2; i >= 0; i-- {
+		parent := copyPersistentNode(path[i])
+		for j, child := range parent.children {
+			if child == path[i+1] {
+				parent.children[j] = replaced
+			}
+		}
+		replaced = parent
+	}
+	return replaced
+}
+
+// Insert inserts a value into the tree.
+// A value equal to one already in the tree is handled according to the tree's DuplicatePolicy.
+// It returns the new version of the tree; the tree it was called on is unchanged.
+func (tree *PersistentTree[T]) Insert(value T) (*PersistentTree[T], error) {
+	if tree.root == nil {
+		return tree.withRoot(newPersistentNode([]T{value}, nil)), nil
+	}
+
+	if tree.duplicates != AllowDuplicates {
+		if path, idx := tree.find(value); path != nil {
+			if tree.duplicates == RejectDuplicates {
+				return tree, ErrDuplicate
+			}
+			return tree.withRoot(tree.replace(path, idx, value)), nil
+		}
+	}
+
+	result := tree.insert(tree.root, value)
+	if result.node != nil {
+		return tree.withRoot(result.node), nil
+	}
+	return tree.withRoot(newPersistentNode([]T{result.mid}, []*persistentNode[T]{result.left, result.right})), nil
+}
+
+// repairPersistent restores the child at pos of the given node after it has lost its only data value.
+// The node must be a fresh copy, as it is modified in place; its siblings are copied before being changed.
+// It borrows a value from an adjacent 3-node sibling when possible, and otherwise merges the
+// empty child into a 2-node sibling, which may leave node itself empty.
+// It returns node with its height and size recalculated.
+func repairPersistent[T any](node *persistentNode[T], pos int) *persistentNode[T] {
+	hole := node.children[pos]
+	if len(hole.data) > 0 {
+		return newPersistentNode(node.data, node.children)
+	}
+	orphans := hole.children
+
+	switch {
+	case pos > 0 && len(node.children[pos-1].data) == 2:
+		// Borrow from the left sibling, rotating through the separator.
+		left := node.children[pos-1]
+		var moved, kept []*persistentNode[T]
+		if len(left.children) == 3 {
+			moved, kept = left.children[2:], left.children[:2:2]
+		}
+		node.children[pos-1] = newPersistentNode(left.data[:1:1], kept)
+		node.children[pos] = newPersistentNode([]T{node.data[pos-1]}, append(append([]*persistentNode[T](nil), moved...), orphans...))
+		node.data[pos-1] = left.data[1]
+	case pos < len(node.children)-1 && len(node.children[pos+1].data) == 2:
+		// Borrow from the right sibling, rotating through the separator.
+		right := node.children[pos+1]
+		var moved, kept []*persistentNode[T]
+		if len(right.children) == 3 {
+			moved, kept = right.children[:1], right.children[1:]
+		}
+		node.children[pos+1] = newPersistentNode(right.data[1:], kept)
+		node.children[pos] = newPersistentNode([]T{node.data[pos]}, append(append([]*persistentNode[T](nil), orphans...), moved...))
+		node.data[pos] = right.data[0]
+	case pos > 0:
+		// Merge into the left sibling, pulling the separator down.
+		left := node.children[pos-1]
+		merged := newPersistentNode(
+			append(append([]T(nil), left.data...), node.data[pos-1]),
+			append(append([]*persistentNode[T](nil), left.children...), orphans...),
+		)
+		node.children[pos-1] = merged
+		node.children = append(node.children[:pos], node.children[pos+1:]...)
+		node.data = append(node.data[:pos-1], node.data[pos:]...)
+	default:
+		// Merge into the right sibling, pulling the separator down.
+		right := node.children[pos+1]
+		merged := newPersistentNode(
+			append([]T{node.data[pos]}, right.data...),
+			append(append([]*persistentNode[T](nil), orphans...), right.children...),
+		)
+		node.children[pos+1] = merged
+		node.children = node.children[1:]
+		node.data = node.data[1:]
+	}
+
+	return newPersistentNode(node.data, node.children)
+}
+
+// removeMin removes the smallest value of the subtree, copying the nodes along the leftmost path.
+// It returns the new subtree, which may be empty, and the value removed.
+func removeMin[T any](node *persistentNode[T]) (*persistentNode[T], T) {
+	if len(node.children) == 0 {
+		return newPersistentNode(node.data[1:], nil), node.data[0]
+	}
+
+	child, min := removeMin(node.children[0])
+	copied := copyPersistentNode(node)
+	copied.children[0] = child
+	return repairPersistent(copied, 0), min
+}
+
+// remove removes the value from the subtree, copying the nodes along the path it descends.
+// It returns the new subtree, which may be empty, and whether the value was found.
+func (tree *PersistentTree[T]) remove(node *persistentNode[T], value T) (*persistentNode[T], bool) {
+	pos := len(node.data)
+	for i, datum := range node.data {
+		c := tree.comparator(value, datum)
+		if c == 0 {
+			copied := copyPersistentNode(node)
+			if len(node.children) == 0 {
+				copied.data = append(copied.data[:i], copied.data[i+1:]...)
+				return newPersistentNode(copied.data, nil), true
+			}
+			// Values are only ever removed from leaves, so an internal value is
+			// replaced with its in-order successor, which is removed instead.
+			child, successor := removeMin(node.children[i+1])
+			copied.data[i] = successor
+			copied.children[i+1] = child
+			return repairPersistent(copied, i+1), true
+		}
+		if c < 0 {
+			pos = i
+			break
+		}
+	}
+
+	if len(node.children) == 0 {
+		return node, false
+	}
+
+	child, found := tree.remove(node.children[pos], value)
+	if !found {
+		return node, false
+	}
+	copied := copyPersistentNode(node)
+	copied.children[pos] = child
+	return repairPersistent(copied, pos), true
+}
+
+// Delete removes a value from the tree.
+// It returns the new version of the tree, and whether the value was found; the tree it was called on is unchanged.
+func (tree *PersistentTree[T]) Delete(value T) (*PersistentTree[T], bool) {
+	if tree.root == nil {
+		return tree, false
+	}
+
+	root, found := tree.remove(tree.root, value)
+	if !found {
+		return tree, false
+	}
+
+	// An empty root is replaced by its only child, if any.
+	if len(root.data) == 0 {
+		if len(root.children) == 0 {
+			return tree.withRoot(nil), true
+		}
+		root = root.children[0]
+	}
+	return tree.withRoot(root), true
+}
+
+// Get finds a value in the tree.
+// It returns the stored value equal to the given one, and whether it was found.
+func (tree *PersistentTree[T]) Get(value T) (T, bool) {
+	var zeroVal T
+	path, idx := tree.find(value)
+	if path == nil {
+		return zeroVal, false
+	}
+	return path[len(path)-1].data[idx], true
+}
+
+// Contains reports whether a value equal to the given one is stored in the tree.
+func (tree *PersistentTree[T]) Contains(value T) bool {
+	path, _ := tree.find(value)
+	return path != nil
+}
+
+// Len returns the number of values stored in the tree.
+func (tree *PersistentTree[T]) Len() int {
+	if tree.root == nil {
+		return 0
+	}
+	return tree.root.size
+}
+
+// Height returns the number of levels in the tree, or 0 if the tree is empty.
+func (tree *PersistentTree[T]) Height() int {
+	if tree.root == nil {
+		return 0
+	}
+	return tree.root.height
+}
+
+// ascend calls fn, in ascending order, for each value of the subtree.
+// It returns false once fn returns false.
+func ascend[T any](node *persistentNode[T], fn func(T) bool) bool {
+	for i, datum := range node.data {
+		if len(node.children) > 0 && !ascend(node.children[i], fn) {
+			return false
+		}
+		if !fn(datum) {
+			return false
+		}
+	}
+	if len(node.children) > 0 {
+		return ascend(node.children[len(node.children)-1], fn)
+	}
+	return true
+}
+
+// Ascend calls fn, in ascending order, for each value of the tree.
+// Iteration stops early if fn returns false.
+func (tree *PersistentTree[T]) Ascend(fn func(T) bool) {
+	if tree.root != nil {
+		ascend(tree.root, fn)
+	}
+}
+
+// InOrder returns the values of the tree in ascending order.
+func (tree *PersistentTree[T]) InOrder() []T {
+	var result []T
+	tree.Ascend(func(value T) bool {
+		result = append(result, value)
+		return true
+	})
+	return result
+}
+
+// ascendRangePersistent calls fn, in ascending order, for each value of the subtree that lies within the range.
+// Children are only descended into when the separators around them overlap the range.
+// It returns false once fn returns false or a value above the range is reached.
+func ascendRangePersistent[T any](node *persistentNode[T], comparator func(T, T) int, lo, hi T, inclusivity Inclusivity, fn func(T) bool) bool {
+	for i := 0; i <= len(node.data); i++ {
+		// child i only holds values between data[i-1] and data[i].
+		if i < len(node.children) {
+			overlapsLow := i == len(node.data) || comparator(node.data[i], lo) >= 0
+			overlapsHigh := i == 0 || comparator(node.data[i-1], hi) <= 0
+			if overlapsLow && overlapsHigh && !ascendRangePersistent(node.children[i], comparator, lo, hi, inclusivity, fn) {
+				return false
+			}
+		}
+
+		if i == len(node.data) {
+			break
+		}
+		if !belowHigh(comparator, node.data[i], hi, inclusivity) {
+			return false
+		}
+		if aboveLow(comparator, node.data[i], lo, inclusivity) && !fn(node.data[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AscendRange calls fn, in ascending order, for each value of the tree in the half-open range [lo, hi).
+// Iteration stops early if fn returns false.
+func (tree *PersistentTree[T]) AscendRange(lo, hi T, fn func(T) bool) {
+	if tree.root != nil {
+		ascendRangePersistent(tree.root, tree.comparator, lo, hi, IncludeLow, fn)
+	}
+}
+
+// Range finds the values of the tree between lo and hi.
+// The inclusivity determines whether values equal to lo or hi are part of the range.
+// It returns the values in ascending order.
+func (tree *PersistentTree[T]) Range(lo, hi T, inclusivity Inclusivity) []T {
+	var result []T
+	if tree.root != nil {
+		ascendRangePersistent(tree.root, tree.comparator, lo, hi, inclusivity, func(value T) bool {
+			result = append(result, value)
+			return true
+		})
+	}
+	return result
+}
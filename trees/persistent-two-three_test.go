@@ -0,0 +1,216 @@
+package trees
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// checkPersistentShape fails the test if the subtree has a malformed node, leaves at different depths,
+// out of order values, or a stale height or size.
+// It returns the height and size of the subtree.
+func checkPersistentShape[T any](t *testing.T, node *persistentNode[T], comparator func(T, T) int) (int, int) {
+	t.Helper()
+	if len(node.data) < 1 || len(node.data) > 2 {
+		t.Fatalf("node has %d values", len(node.data))
+	}
+	if len(node.children) != 0 && len(node.children) != len(node.data)+1 {
+		t.Fatalf("node has %d values and %d children", len(node.data), len(node.children))
+	}
+	if len(node.data) == 2 && comparator(node.data[0], node.data[1]) > 0 {
+		t.Fatalf("node values %v are out of order", node.data)
+	}
+
+	height, size := 0, len(node.data)
+	for i, child := range node.children {
+		childHeight, childSize := checkPersistentShape(t, child, comparator)
+		if height != 0 && childHeight != height {
+			t.Fatalf("children have different heights")
+		}
+		if i > 0 && comparator(child.data[0], node.data[i-1]) < 0 {
+			t.Fatalf("child value %v is less than separator %v", child.data[0], node.data[i-1])
+		}
+		if i < len(node.data) && comparator(child.data[len(child.data)-1], node.data[i]) > 0 {
+			t.Fatalf("child value %v is greater than separator %v", child.data[len(child.data)-1], node.data[i])
+		}
+		height = childHeight
+		size += childSize
+	}
+	if node.height != height+1 || node.size != size {
+		t.Fatalf("node height, size = %d, %d, want %d, %d", node.height, node.size, height+1, size)
+	}
+	return height + 1, size
+}
+
+func TestPersistentTreeVersions(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	type version struct {
+		tree *PersistentTree[int]
+		want []int
+	}
+	tree := NewPersistentTree(intComparator)
+	model := []int{}
+	var versions []version
+
+	for step := 0; step < 2000; step++ {
+		value := r.Intn(300)
+		if r.Intn(3) == 0 {
+			var found bool
+			tree, found = tree.Delete(value)
+			idx := sort.SearchInts(model, value)
+			wantFound := idx < len(model) && model[idx] == value
+			if found != wantFound {
+				t.Fatalf("Delete(%d) found = %v, want %v", value, found, wantFound)
+			}
+			if found {
+				model = append(append([]int{}, model[:idx]...), model[idx+1:]...)
+			}
+		} else {
+			var err error
+			if tree, err = tree.Insert(value); err != nil {
+				t.Fatalf("Insert() error = %v", err)
+			}
+			idx := sort.SearchInts(model, value+1)
+			model = append(append(append([]int{}, model[:idx]...), value), model[idx:]...)
+		}
+
+		if tree.root != nil {
+			checkPersistentShape(t, tree.root, intComparator)
+		}
+		if tree.Len() != len(model) {
+			t.Fatalf("Len() = %v, want %v", tree.Len(), len(model))
+		}
+		versions = append(versions, version{tree, model})
+	}
+
+	// Every old version still holds exactly what it held when it was created.
+	for i, v := range versions {
+		if got := v.tree.InOrder(); !reflect.DeepEqual(got, v.want) && !(len(got) == 0 && len(v.want) == 0) {
+			t.Fatalf("version %d holds %v, want %v", i, got, v.want)
+		}
+	}
+}
+
+// countNodes adds every node of the subtree to seen.
+func countNodes[T any](node *persistentNode[T], seen map[*persistentNode[T]]bool) {
+	seen[node] = true
+	for _, child := range node.children {
+		countNodes(child, seen)
+	}
+}
+
+func TestPersistentTreeSharesNodes(t *testing.T) {
+	tree := NewPersistentTree(intComparator)
+	for _, value := range rand.New(rand.NewSource(1)).Perm(1000) {
+		tree, _ = tree.Insert(value * 2)
+	}
+
+	before := map[*persistentNode[int]]bool{}
+	countNodes(tree.root, before)
+
+	for _, next := range []*PersistentTree[int]{
+		must(tree.Insert(501)),
+		first(tree.Delete(500)),
+	} {
+		after := map[*persistentNode[int]]bool{}
+		countNodes(next.root, after)
+
+		copied := 0
+		for node := range after {
+			if !before[node] {
+				copied++
+			}
+		}
+		if limit := 2*tree.Height() + 2; copied > limit {
+			t.Errorf("update copied %d nodes, want at most %d", copied, limit)
+		}
+	}
+}
+
+func must[T any](value T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+func first[T, U any](value T, _ U) T {
+	return value
+}
+
+func TestPersistentTreePolicies(t *testing.T) {
+	reject := NewPersistentTreeWithPolicy(recordComparator, RejectDuplicates)
+	reject, _ = reject.Insert(record{1, 1})
+	if same, err := reject.Insert(record{1, 2}); !errors.Is(err, ErrDuplicate) || same != reject {
+		t.Errorf("Insert() = %v, want the same tree and ErrDuplicate", err)
+	}
+
+	replace := NewPersistentTreeWithPolicy(recordComparator, ReplaceDuplicates)
+	for i := 0; i < 20; i++ {
+		replace, _ = replace.Insert(record{i, 0})
+	}
+	replaced, _ := replace.Insert(record{7, 1})
+	if got, _ := replaced.Get(record{key: 7}); got.id != 1 {
+		t.Errorf("Get() = %v, want the replacement", got)
+	}
+	if got, _ := replace.Get(record{key: 7}); got.id != 0 {
+		t.Errorf("Get() on the old version = %v, want the original", got)
+	}
+	if replaced.Len() != 20 {
+		t.Errorf("Len() = %v, want 20", replaced.Len())
+	}
+}
+
+func TestPersistentTreeEmpty(t *testing.T) {
+	tree := NewPersistentTree(intComparator)
+	if tree.Len() != 0 || tree.Height() != 0 || tree.Contains(1) {
+		t.Errorf("empty tree is not empty")
+	}
+	if same, found := tree.Delete(1); found || same != tree {
+		t.Errorf("Delete() on an empty tree found a value")
+	}
+
+	one, _ := tree.Insert(1)
+	if empty, found := one.Delete(1); !found || empty.Len() != 0 || one.Len() != 1 {
+		t.Errorf("Delete() of the last value = %v, %v", empty.Len(), found)
+	}
+}
+
+func TestPersistentTreeRange(t *testing.T) {
+	tree := NewPersistentTree(intComparator)
+	var values []int
+	for i := 0; i < 60; i++ {
+		value := (i * 7) % 30
+		values = append(values, value)
+		tree = must(tree.Insert(value))
+	}
+	sort.Ints(values)
+
+	for _, inclusivity := range []Inclusivity{ExcludeBoth, IncludeLow, IncludeHigh, IncludeBoth} {
+		for lo := -1; lo <= 30; lo += 4 {
+			for hi := lo; hi <= 31; hi += 3 {
+				var want []int
+				for _, value := range values {
+					if aboveLow(intComparator, value, lo, inclusivity) && belowHigh(intComparator, value, hi, inclusivity) {
+						want = append(want, value)
+					}
+				}
+				if got := tree.Range(lo, hi, inclusivity); !reflect.DeepEqual(got, want) {
+					t.Errorf("Range(%d, %d, %d) = %v, want %v", lo, hi, inclusivity, got, want)
+				}
+			}
+		}
+	}
+
+	var got []int
+	tree.AscendRange(10, 20, func(value int) bool {
+		got = append(got, value)
+		return len(got) < 3
+	})
+	if !reflect.DeepEqual(got, []int{10, 10, 11}) {
+		t.Errorf("AscendRange() = %v, want [10 10 11]", got)
+	}
+}
@@ -0,0 +1,472 @@
+package trees
+
+import "fmt"
+
+/* RedBlackTree is a left-leaning red-black tree, the binary encoding of a 2-3-4 tree described by Sedgewick.
+Each node of a 2-3-4 tree becomes a black node with up to two red children:
+
+	[b]        becomes  b
+	[a b]      becomes  b with a red left child a
+	[a b c]    becomes  b with red children a and c
+
+so a red link never leans right unless its sibling is red too, no path has two red links in a row,
+and every path from the root to a leaf crosses the same number of black links.
+Inserts split 4-nodes on the way down, as TwoThreeFour does, so inserting the same values into both
+trees builds shapes that ToRedBlack and ToTwoThreeFour map onto each other. Deletes push a red link
+down the search path and leave 4-nodes in place, so the tree is a valid 2-3-4 encoding after every
+operation, though deletes restructure differently from those of TwoThreeFour.
+*/
+
+// redBlackNode is a node of a RedBlackTree.
+type redBlackNode[T any] struct {
+	value       T
+	left, right *redBlackNode[T]
+
+	// red is the colour of the link from the node's parent, and is false for the root.
+	red bool
+}
+
+func isRed[T any](node *redBlackNode[T]) bool {
+	return node != nil && node.red
+}
+
+// rotateLeft turns a right-leaning red link of node into a left-leaning one.
+func rotateLeft[T any](node *redBlackNode[T]) *redBlackNode[T] {
+	x := node.right
+	node.right = x.left
+	x.left = node
+	x.red = node.red
+	node.red = true
+	return x
+}
+
+// rotateRight turns a left-leaning red link of node into a right-leaning one.
+func rotateRight[T any](node *redBlackNode[T]) *redBlackNode[T] {
+	x := node.left
+	node.left = x.right
+	x.right = node
+	x.red = node.red
+	node.red = true
+	return x
+}
+
+// flipColors flips the colours of a node and its two children, splitting a 4-node into
+// two 2-nodes and passing its middle value up, or the reverse.
+func flipColors[T any](node *redBlackNode[T]) {
+	node.red = !node.red
+	node.left.red = !node.left.red
+	node.right.red = !node.right.red
+}
+
+// fixUp restores the left-leaning invariants of node on the way back up from an insertion or deletion.
+// Balanced 4-nodes are left in place, rather than split as in the 2-3 variant of the tree.
+func fixUp[T any](node *redBlackNode[T]) *redBlackNode[T] {
+	if isRed(node.right) && !isRed(node.left) {
+		node = rotateLeft(node)
+	}
+	if isRed(node.left) && isRed(node.left.left) {
+		node = rotateRight(node)
+	}
+	return node
+}
+
+// moveRedLeft makes the left child of node, or one of its children, red, borrowing from the right sibling if it can.
+func moveRedLeft[T any](node *redBlackNode[T]) *redBlackNode[T] {
+	flipColors(node)
+	if isRed(node.right.left) {
+		node.right = rotateRight(node.right)
+		node = rotateLeft(node)
+		flipColors(node)
+		// Borrowing from a 4-node leaves its last value leaning right, off the search path that fixUp repairs.
+		if isRed(node.right.right) {
+			node.right = rotateLeft(node.right)
+		}
+	}
+	return node
+}
+
+// moveRedRight makes the right child of node, or one of its children, red, borrowing from the left sibling if it can.
+func moveRedRight[T any](node *redBlackNode[T]) *redBlackNode[T] {
+	flipColors(node)
+	if isRed(node.left.left) {
+		node = rotateRight(node)
+		flipColors(node)
+	}
+	return node
+}
+
+// RedBlackTree is a left-leaning red-black tree.
+// The zero value is not usable; call NewRedBlackTree.
+type RedBlackTree[T any] struct {
+	// root is nil while the tree is empty.
+	root *redBlackNode[T]
+
+	// comparator is used to compare two values.
+	// returns -1 if a < b, 0 if a == b, 1 if a > b
+	comparator func(T, T) int
+
+	// duplicates decides what Insert does with a value equal to one already in the tree.
+	duplicates DuplicatePolicy
+
+	// size is the number of values stored in the tree.
+	size int
+}
+
+// NewRedBlackTree is a constructor for an empty left-leaning red-black tree ordered by the given comparator.
+// The tree allows duplicate values.
+func NewRedBlackTree[T any](comparator func(a, b T) int) *RedBlackTree[T] {
+	return NewRedBlackTreeWithPolicy(comparator, AllowDuplicates)
+}
+
+// NewRedBlackTreeWithPolicy is a constructor for an empty left-leaning red-black tree ordered by the given comparator,
+// which handles duplicate values according to the given policy.
+func NewRedBlackTreeWithPolicy[T any](comparator func(a, b T) int, duplicates DuplicatePolicy) *RedBlackTree[T] {
+	return &RedBlackTree[T]{
+		root:       nil,
+		comparator: comparator,
+		duplicates: duplicates,
+		size:       0,
+	}
+}
+
+// find returns the node holding a value equal to the given one, or nil.
+func (tree *RedBlackTree[T]) find(value T) *redBlackNode[T] {
+	node := tree.root
+	for node != nil {
+		c := tree.comparator(value, node.value)
+		switch {
+		case c == 0:
+			return node
+		case c < 0:
+			node = node.left
+		default:
+			node = node.right
+		}
+	}
+	return nil
+}
+
+// Len returns the number of values stored in the tree.
+func (tree *RedBlackTree[T]) Len() int {
+	return tree.size
+}
+
+// Height returns the number of levels in the tree, or 0 if the tree is empty.
+// Counting only black links, as a 2-3-4 tree would, the height is at most half of this.
+func (tree *RedBlackTree[T]) Height() int {
+	var height func(node *redBlackNode[T]) int
+	height = func(node *redBlackNode[T]) int {
+		if node == nil {
+			return 0
+		}
+		left, right := height(node.left), height(node.right)
+		if left > right {
+			return left + 1
+		}
+		return right + 1
+	}
+	return height(tree.root)
+}
+
+// Get finds a value in the tree.
+// It returns the stored value equal to the given one, and whether it was found.
+func (tree *RedBlackTree[T]) Get(value T) (T, bool) {
+	var zeroVal T
+	if node := tree.find(value); node != nil {
+		return node.value, true
+	}
+	return zeroVal, false
+}
+
+// Contains reports whether a value equal to the given one is stored in the tree.
+func (tree *RedBlackTree[T]) Contains(value T) bool {
+	return tree.find(value) != nil
+}
+
+// insert inserts the value into the subtree, placing it after any values equal to it.
+// It returns the new root of the subtree.
+func (tree *RedBlackTree[T]) insert(node *redBlackNode[T], value T) *redBlackNode[T] {
+	if node == nil {
+		return &redBlackNode[T]{value: value, red: true}
+	}
+
+	// Split a 4-node on the way down.
+	if isRed(node.left) && isRed(node.right) {
+		flipColors(node)
+	}
+
+	if tree.comparator(value, node.value) < 0 {
+		node.left = tree.insert(node.left, value)
+	} else {
+		node.right = tree.insert(node.right, value)
+	}
+	return fixUp(node)
+}
+
+// Insert inserts a value into the tree.
+// A value equal to one already in the tree is handled according to the tree's DuplicatePolicy.
+func (tree *RedBlackTree[T]) Insert(value T) error {
+	if tree.duplicates != AllowDuplicates {
+		if node := tree.find(value); node != nil {
+			if tree.duplicates == RejectDuplicates {
+				return ErrDuplicate
+			}
+			node.value = value
+			return nil
+		}
+	}
+
+	tree.root = tree.insert(tree.root, value)
+	tree.root.red = false
+	tree.size++
+	return nil
+}
+
+// deleteMin removes the smallest value of the subtree.
+// It returns the new root of the subtree.
+func deleteMin[T any](node *redBlackNode[T]) *redBlackNode[T] {
+	if node.left == nil {
+		return nil
+	}
+	if !isRed(node.left) && !isRed(node.left.left) {
+		node = moveRedLeft(node)
+	}
+	node.left = deleteMin(node.left)
+	return fixUp(node)
+}
+
+// delete removes a value equal to the given one from the subtree, which must hold one.
+// Red links are pushed down the search path, so the value is removed from a 3-node or 4-node.
+// It returns the new root of the subtree.
+func (tree *RedBlackTree[T]) delete(node *redBlackNode[T], value T) *redBlackNode[T] {
+	if tree.comparator(value, node.value) < 0 {
+		if !isRed(node.left) && !isRed(node.left.left) {
+			node = moveRedLeft(node)
+		}
+		node.left = tree.delete(node.left, value)
+		return fixUp(node)
+	}
+
+	// Lean a 3-node right, so the search path holds a red link; a 4-node already has one.
+	if isRed(node.left) && !isRed(node.right) {
+		node = rotateRight(node)
+	}
+	if tree.comparator(value, node.value) == 0 && node.right == nil {
+		return nil
+	}
+	if !isRed(node.right) && !isRed(node.right.left) {
+		moved := moveRedRight(node)
+		if moved != node {
+			// A rotation brought up a value no greater than the one being deleted, while the right subtree
+			// now holds the old node; with duplicates the two may be equal, so keep descending right.
+			moved.right = tree.delete(moved.right, value)
+			return fixUp(moved)
+		}
+	}
+	if tree.comparator(value, node.value) == 0 {
+		// The value is replaced by its in-order successor, which is removed instead.
+		successor := node.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		node.value = successor.value
+		node.right = deleteMin(node.right)
+	} else {
+		node.right = tree.delete(node.right, value)
+	}
+	return fixUp(node)
+}
+
+// Delete removes a value from the tree.
+// It returns whether the value was found.
+func (tree *RedBlackTree[T]) Delete(value T) bool {
+	if !tree.Contains(value) {
+		return false
+	}
+
+	if !isRed(tree.root.left) && !isRed(tree.root.right) {
+		tree.root.red = true
+	}
+	tree.root = tree.delete(tree.root, value)
+	if tree.root != nil {
+		tree.root.red = false
+	}
+	tree.size--
+	return true
+}
+
+// ascendRedBlack calls fn for each value of the subtree in ascending order.
+// It returns false once fn returns false.
+func ascendRedBlack[T any](node *redBlackNode[T], fn func(T) bool) bool {
+	if node == nil {
+		return true
+	}
+	return ascendRedBlack(node.left, fn) && fn(node.value) && ascendRedBlack(node.right, fn)
+}
+
+// Ascend calls fn for each value of the tree in ascending order.
+// Iteration stops early if fn returns false.
+func (tree *RedBlackTree[T]) Ascend(fn func(T) bool) {
+	ascendRedBlack(tree.root, fn)
+}
+
+// InOrder returns the values of the tree in ascending order.
+func (tree *RedBlackTree[T]) InOrder() []T {
+	result := make([]T, 0, tree.size)
+	tree.Ascend(func(value T) bool {
+		result = append(result, value)
+		return true
+	})
+	return result
+}
+
+// BFS traverses the tree in breadth-first order.
+// It returns the value of each node in the order visited.
+func (tree *RedBlackTree[T]) BFS() []T {
+	var result []T
+	if tree.root == nil {
+		return result
+	}
+	queue := []*redBlackNode[T]{tree.root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		result = append(result, node.value)
+		for _, child := range []*redBlackNode[T]{node.left, node.right} {
+			if child != nil {
+				queue = append(queue, child)
+			}
+		}
+	}
+	return result
+}
+
+// validate checks the subtree rooted at node, whose values must lie between lo and hi (when not nil).
+// It returns the number of black links on every path from node down to a leaf.
+func (tree *RedBlackTree[T]) validate(v *validator[T], node *redBlackNode[T], path string, lo, hi *T) int {
+	if node == nil {
+		return 0
+	}
+	v.count++
+
+	if lo != nil && tree.comparator(node.value, *lo) < 0 {
+		v.report(path, "value %v is less than the value %v above it", node.value, *lo)
+	}
+	if hi != nil && tree.comparator(node.value, *hi) > 0 {
+		v.report(path, "value %v is greater than the value %v above it", node.value, *hi)
+	}
+	if node.red && (isRed(node.left) || isRed(node.right)) {
+		v.report(path, "red node has a red child")
+	}
+	if isRed(node.right) && !isRed(node.left) {
+		v.report(path, "red link leans right")
+	}
+
+	left := tree.validate(v, node.left, path+".0", lo, &node.value)
+	right := tree.validate(v, node.right, path+".1", &node.value, hi)
+	if left != right {
+		v.report(path, "left subtree has %d black link(s), but the right has %d", left, right)
+	}
+	if !node.red {
+		left++
+	}
+	return left
+}
+
+// Validate walks the whole tree and checks every invariant of a left-leaning red-black tree:
+// the root is black, no red node has a red child, a red right child always has a red sibling,
+// every path from the root crosses the same number of black nodes, values are in order, and the tree holds Len values.
+// It returns nil if the tree is valid, or a *ValidationError listing every violation.
+func (tree *RedBlackTree[T]) Validate() error {
+	v := &validator[T]{leafDepth: -1}
+	if isRed(tree.root) {
+		v.report("root", "root is red")
+	}
+	tree.validate(v, tree.root, "root", nil, nil)
+	if v.count != tree.size {
+		v.report("root", "tree holds %d value(s), but Len is %d", v.count, tree.size)
+	}
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+	return nil
+}
+
+// toRedBlack encodes a node of a 2-3-4 tree as a black node with up to two red children.
+func toRedBlack[T any](node *bTreeNode[T]) *redBlackNode[T] {
+	if node == nil {
+		return nil
+	}
+
+	child := func(i int) *redBlackNode[T] {
+		if node.isLeaf() {
+			return nil
+		}
+		return toRedBlack(node.children[i])
+	}
+
+	switch len(node.keys) {
+	case 1:
+		return &redBlackNode[T]{value: node.keys[0], left: child(0), right: child(1)}
+	case 2:
+		left := &redBlackNode[T]{value: node.keys[0], left: child(0), right: child(1), red: true}
+		return &redBlackNode[T]{value: node.keys[1], left: left, right: child(2)}
+	default:
+		left := &redBlackNode[T]{value: node.keys[0], left: child(0), right: child(1), red: true}
+		right := &redBlackNode[T]{value: node.keys[2], left: child(2), right: child(3), red: true}
+		return &redBlackNode[T]{value: node.keys[1], left: left, right: right}
+	}
+}
+
+// toTwoThreeFour decodes a black node, together with its red children, into a node of a 2-3-4 tree.
+func toTwoThreeFour[T any](node *redBlackNode[T]) *bTreeNode[T] {
+	if node == nil {
+		return nil
+	}
+
+	// Red children are part of the same 2-3-4 node; black ones are its children.
+	var parts []*redBlackNode[T]
+	if isRed(node.left) {
+		parts = append(parts, node.left.left, node.left, node.left.right)
+	} else {
+		parts = append(parts, node.left)
+	}
+	parts = append(parts, node)
+	if isRed(node.right) {
+		parts = append(parts, node.right.left, node.right, node.right.right)
+	} else {
+		parts = append(parts, node.right)
+	}
+
+	out := &bTreeNode[T]{}
+	for i, part := range parts {
+		if i%2 == 1 {
+			out.keys = append(out.keys, part.value)
+		} else if part != nil {
+			out.children = append(out.children, toTwoThreeFour(part))
+		}
+	}
+	return out
+}
+
+// ToRedBlack returns the left-leaning red-black encoding of the 2-3-4 tree, which is left unchanged.
+func ToRedBlack[T any](tree *TwoThreeFour[T]) *RedBlackTree[T] {
+	return &RedBlackTree[T]{
+		root:       toRedBlack(tree.tree.root),
+		comparator: tree.tree.comparator,
+		duplicates: tree.tree.duplicates,
+		size:       tree.tree.size,
+	}
+}
+
+// ToTwoThreeFour returns the 2-3-4 tree encoded by the left-leaning red-black tree, which is left unchanged.
+// It returns an error listing the violations if the red-black tree is not valid, as it then encodes no 2-3-4 tree.
+func ToTwoThreeFour[T any](tree *RedBlackTree[T]) (*TwoThreeFour[T], error) {
+	if err := tree.Validate(); err != nil {
+		return nil, fmt.Errorf("red-black tree does not encode a 2-3-4 tree: %w", err)
+	}
+	out := NewTwoThreeFourWithPolicy(tree.comparator, tree.duplicates)
+	out.tree.root = toTwoThreeFour(tree.root)
+	out.tree.size = tree.size
+	return out, nil
+}
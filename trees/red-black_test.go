@@ -0,0 +1,189 @@
+package trees
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// shape describes a 2-3-4 tree as nested lists of keys, e.g. "[20 [10] [30 40]]".
+func shape[T any](node *bTreeNode[T]) string {
+	if node == nil {
+		return "[]"
+	}
+	s := fmt.Sprint(node.keys)
+	for _, child := range node.children {
+		s += " " + shape(child)
+	}
+	return "[" + s + "]"
+}
+
+func TestRedBlackTreeRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	tree := NewRedBlackTree(intComparator)
+	var want []int
+
+	for i := 0; i < 3000; i++ {
+		value := rng.Intn(500)
+		if rng.Intn(3) == 0 {
+			j := sort.SearchInts(want, value)
+			found := j < len(want) && want[j] == value
+			if found {
+				want = append(want[:j], want[j+1:]...)
+			}
+			if got := tree.Delete(value); got != found {
+				t.Fatalf("Delete(%d) = %v, want %v", value, got, found)
+			}
+		} else {
+			if err := tree.Insert(value); err != nil {
+				t.Fatal(err)
+			}
+			want = insertAt(want, sort.SearchInts(want, value+1), value)
+		}
+
+		if err := tree.Validate(); err != nil {
+			t.Fatalf("after step %d: %v", i, err)
+		}
+	}
+
+	if got := tree.InOrder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("InOrder() = %v, want %v", got, want)
+	}
+	for _, value := range want {
+		if !tree.Delete(value) {
+			t.Fatalf("Delete(%d) did not find the value", value)
+		}
+	}
+	if tree.Len() != 0 || tree.Height() != 0 {
+		t.Errorf("tree is not empty after deleting every value")
+	}
+}
+
+func TestRedBlackTreeDuplicates(t *testing.T) {
+	for seed := int64(0); seed < 30; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		tree := NewRedBlackTree(intComparator)
+		counts := map[int]int{}
+
+		for i := 0; i < 1000; i++ {
+			// Few distinct values, so deletions meet runs of equal values across rotations.
+			value := rng.Intn(1 + rng.Intn(40))
+			if rng.Intn(2) == 0 {
+				if got := tree.Delete(value); got != (counts[value] > 0) {
+					t.Fatalf("seed %d: Delete(%d) = %v with %d copies", seed, value, got, counts[value])
+				}
+				if counts[value] > 0 {
+					counts[value]--
+				}
+			} else {
+				if err := tree.Insert(value); err != nil {
+					t.Fatal(err)
+				}
+				counts[value]++
+			}
+
+			if err := tree.Validate(); err != nil {
+				t.Fatalf("seed %d, after step %d: %v", seed, i, err)
+			}
+		}
+	}
+}
+
+func TestRedBlackTreePolicies(t *testing.T) {
+	reject := NewRedBlackTreeWithPolicy(recordComparator, RejectDuplicates)
+	replace := NewRedBlackTreeWithPolicy(recordComparator, ReplaceDuplicates)
+	for i := 0; i < 20; i++ {
+		if err := reject.Insert(record{i, 0}); err != nil {
+			t.Fatal(err)
+		}
+		if err := replace.Insert(record{i, 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := reject.Insert(record{7, 1}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Insert() error = %v, want %v", err, ErrDuplicate)
+	}
+	if err := replace.Insert(record{7, 1}); err != nil {
+		t.Errorf("Insert() error = %v", err)
+	}
+	if got, ok := replace.Get(record{key: 7}); !ok || got.id != 1 {
+		t.Errorf("Get() = %v, want the replacement", got)
+	}
+}
+
+func TestRedBlackTreeMatchesTwoThreeFour(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		twoThreeFour := NewTwoThreeFour(intComparator)
+		redBlack := NewRedBlackTree(intComparator)
+
+		for i := 0; i < 300; i++ {
+			value := rng.Intn(100)
+			if err := twoThreeFour.Insert(value); err != nil {
+				t.Fatal(err)
+			}
+			if err := redBlack.Insert(value); err != nil {
+				t.Fatal(err)
+			}
+
+			decoded, err := ToTwoThreeFour(redBlack)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := shape(decoded.tree.root), shape(twoThreeFour.tree.root); got != want {
+				t.Fatalf("seed %d, after inserting %d values:\nred-black tree encodes %v\nwant %v", seed, i+1, got, want)
+			}
+			roundTrip, err := ToTwoThreeFour(ToRedBlack(twoThreeFour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := shape(roundTrip.tree.root), shape(twoThreeFour.tree.root); got != want {
+				t.Fatalf("round trip = %v, want %v", got, want)
+			}
+		}
+	}
+}
+
+func TestToRedBlack(t *testing.T) {
+	tree := NewTwoThreeFour(intComparator)
+	for _, value := range []int{20, 10, 30, 40, 50, 60, 5, 15} {
+		if err := tree.Insert(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The 2-3-4 tree is [20 40] over [5 10 15], [30] and [50 60].
+	if got, want := shape(tree.tree.root), "[[20 40] [[5 10 15]] [[30]] [[50 60]]]"; got != want {
+		t.Fatalf("shape = %v, want %v", got, want)
+	}
+
+	redBlack := ToRedBlack(tree)
+	if err := redBlack.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := redBlack.BFS(), []int{40, 20, 60, 10, 30, 50, 5, 15}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BFS() = %v, want %v", got, want)
+	}
+	if redBlack.Len() != tree.Len() || !reflect.DeepEqual(redBlack.InOrder(), tree.InOrder()) {
+		t.Errorf("ToRedBlack() changed the values of the tree")
+	}
+}
+
+func TestToTwoThreeFourRejectsInvalidTrees(t *testing.T) {
+	tree := NewRedBlackTree(intComparator)
+	for _, value := range []int{1, 2, 3} {
+		if err := tree.Insert(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tree.root.right.red = true
+	tree.root.left.red = false
+
+	var validationErr *ValidationError
+	if _, err := ToTwoThreeFour(tree); !errors.As(err, &validationErr) {
+		t.Errorf("ToTwoThreeFour() error = %v, want a *ValidationError", err)
+	}
+}
@@ -0,0 +1,194 @@
+package trees
+
+// Monoid describes how to summarise the values of a tree, e.g. to sum, count, or find the largest of them.
+// Combine must be associative, and Identity must leave any summary unchanged when combined with it.
+// Combine need not be commutative: summaries are always combined in ascending order of the values.
+type Monoid[T, S any] struct {
+	// Identity is the summary of no values.
+	Identity S
+
+	// Combine returns the summary of the values summarised by a, followed by those summarised by b.
+	Combine func(a, b S) S
+
+	// Measure returns the summary of a single value.
+	Measure func(value T) S
+}
+
+// AugmentedTree is a two-three tree that keeps a summary of every subtree under a Monoid,
+// such as the sum or the maximum of some measure of its values. The summaries are updated on the way
+// as values are inserted and deleted, so the summary of any range of values can be found in O(log n).
+// The zero value is not usable; call NewAugmentedTree.
+type AugmentedTree[T, S any] struct {
+	tree   *TwoThreeTree[augmentedValue[T, S]]
+	monoid Monoid[T, S]
+}
+
+// augmentedValue is a value of an AugmentedTree as the underlying two-three tree stores it.
+// The first value of each node carries the summary of the node's subtree, so plain two-three trees
+// need no room for summaries in their nodes.
+type augmentedValue[T, S any] struct {
+	value T
+
+	// summary is the summary of the subtree of the node this is the first value of.
+	summary S
+}
+
+// NewAugmentedTree is a constructor for an empty two-three tree ordered by the given comparator,
+// which summarises its values with the given monoid.
+// The tree allows duplicate values.
+func NewAugmentedTree[T, S any](comparator func(a, b T) int, monoid Monoid[T, S]) *AugmentedTree[T, S] {
+	return NewAugmentedTreeWithPolicy(comparator, monoid, AllowDuplicates)
+}
+
+// NewAugmentedTreeWithPolicy is a constructor for an empty two-three tree ordered by the given comparator,
+// which summarises its values with the given monoid, and handles duplicate values according to the given policy.
+func NewAugmentedTreeWithPolicy[T, S any](comparator func(a, b T) int, monoid Monoid[T, S], duplicates DuplicatePolicy) *AugmentedTree[T, S] {
+	tree := &AugmentedTree[T, S]{
+		tree: NewTwoThreeTreeWithPolicy(func(a, b augmentedValue[T, S]) int {
+			return comparator(a.value, b.value)
+		}, duplicates),
+		monoid: monoid,
+	}
+	tree.tree.onUpdate = tree.summarize
+	return tree
+}
+
+// summaryOf returns the summary of the subtree rooted at node, which is Identity for nil.
+func (tree *AugmentedTree[T, S]) summaryOf(node *TwoThreeNode[augmentedValue[T, S]]) S {
+	if node == nil || node.firstData == nil {
+		return tree.monoid.Identity
+	}
+	return node.firstData.summary
+}
+
+// summarize recalculates the summary of the subtree rooted at node from its values and the summaries of its children.
+// A node is left alone while it has no values, which it only has part way through a delete.
+func (tree *AugmentedTree[T, S]) summarize(node *TwoThreeNode[augmentedValue[T, S]]) {
+	if node.firstData == nil {
+		return
+	}
+
+	m := tree.monoid
+	children := nodeChildren(node)
+	summary := m.Identity
+	for i, datum := range nodeData(node) {
+		if i < len(children) {
+			summary = m.Combine(summary, tree.summaryOf(children[i]))
+		}
+		summary = m.Combine(summary, m.Measure(datum.value))
+	}
+	if len(children) > 0 {
+		summary = m.Combine(summary, tree.summaryOf(children[len(children)-1]))
+	}
+	node.firstData.summary = summary
+}
+
+// aggregate returns the summary of the values of the subtree within the range.
+// A nil bound is already known to hold for every value of the subtree, so at most two paths
+// from the root reach nodes that lie partly outside the range, and every other subtree is
+// summarised by the summary kept for it.
+func (tree *AugmentedTree[T, S]) aggregate(node *TwoThreeNode[augmentedValue[T, S]], lo, hi *augmentedValue[T, S], inclusivity Inclusivity) S {
+	m := tree.monoid
+	if node == nil {
+		return m.Identity
+	}
+	if lo == nil && hi == nil {
+		return tree.summaryOf(node)
+	}
+
+	children := nodeChildren(node)
+	data := nodeData(node)
+	summary := m.Identity
+	for i := 0; i <= len(data); i++ {
+		// child i only holds values between data[i-1] and data[i], inclusive.
+		if i < len(children) {
+			skip := (i < len(data) && lo != nil && !aboveLow(node.comparator, *data[i], *lo, inclusivity)) ||
+				(i > 0 && hi != nil && !belowHigh(node.comparator, *data[i-1], *hi, inclusivity))
+			if !skip {
+				childLo, childHi := lo, hi
+				if i > 0 && lo != nil && aboveLow(node.comparator, *data[i-1], *lo, inclusivity) {
+					childLo = nil
+				}
+				if i < len(data) && hi != nil && belowHigh(node.comparator, *data[i], *hi, inclusivity) {
+					childHi = nil
+				}
+				summary = m.Combine(summary, tree.aggregate(children[i], childLo, childHi, inclusivity))
+			}
+		}
+
+		if i == len(data) {
+			break
+		}
+		if (lo == nil || aboveLow(node.comparator, *data[i], *lo, inclusivity)) &&
+			(hi == nil || belowHigh(node.comparator, *data[i], *hi, inclusivity)) {
+			summary = m.Combine(summary, m.Measure(data[i].value))
+		}
+	}
+	return summary
+}
+
+// root returns the root node of the underlying two-three tree, or nil if the tree is empty.
+func (tree *AugmentedTree[T, S]) root() *TwoThreeNode[augmentedValue[T, S]] {
+	return tree.tree.root
+}
+
+// Insert inserts a value into the tree.
+// A value equal to one already in the tree is handled according to the tree's DuplicatePolicy.
+func (tree *AugmentedTree[T, S]) Insert(value T) error {
+	return tree.tree.Insert(augmentedValue[T, S]{value: value})
+}
+
+// Delete removes a value from the tree.
+// It returns whether the value was found.
+func (tree *AugmentedTree[T, S]) Delete(value T) (bool, error) {
+	return tree.tree.Delete(augmentedValue[T, S]{value: value})
+}
+
+// Get finds a value in the tree.
+// It returns the stored value equal to the given one, and whether it was found.
+func (tree *AugmentedTree[T, S]) Get(value T) (T, bool) {
+	found, ok := tree.tree.Get(augmentedValue[T, S]{value: value})
+	return found.value, ok
+}
+
+// Contains reports whether a value equal to the given one is stored in the tree.
+func (tree *AugmentedTree[T, S]) Contains(value T) bool {
+	return tree.tree.Contains(augmentedValue[T, S]{value: value})
+}
+
+// Len returns the number of values stored in the tree.
+func (tree *AugmentedTree[T, S]) Len() int {
+	return tree.tree.Len()
+}
+
+// Height returns the number of levels in the tree, or 0 if the tree is empty.
+func (tree *AugmentedTree[T, S]) Height() int {
+	return tree.tree.Height()
+}
+
+// InOrder returns the values of the tree in ascending order.
+func (tree *AugmentedTree[T, S]) InOrder() []T {
+	stored := InOrder(tree.root())
+	values := make([]T, len(stored))
+	for i, value := range stored {
+		values[i] = value.value
+	}
+	return values
+}
+
+// Validate checks every structural invariant of the tree, as well as its element count.
+// It returns nil if the tree is valid, or a *ValidationError listing every violation.
+func (tree *AugmentedTree[T, S]) Validate() error {
+	return tree.tree.Validate()
+}
+
+// Total returns the summary of every value in the tree, in constant time.
+func (tree *AugmentedTree[T, S]) Total() S {
+	return tree.summaryOf(tree.root())
+}
+
+// Aggregate returns the summary of the values of the tree between lo and hi, in O(log n).
+// The inclusivity determines whether values equal to lo or hi are part of the range.
+func (tree *AugmentedTree[T, S]) Aggregate(lo, hi T, inclusivity Inclusivity) S {
+	return tree.aggregate(tree.root(), &augmentedValue[T, S]{value: lo}, &augmentedValue[T, S]{value: hi}, inclusivity)
+}
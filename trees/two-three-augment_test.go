@@ -0,0 +1,226 @@
+package trees
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type transfer struct {
+	at, bytes int
+}
+
+func transferComparator(a, b transfer) int {
+	return intComparator(a.at, b.at)
+}
+
+var sumBytes = Monoid[transfer, int]{
+	Identity: 0,
+	Combine:  func(a, b int) int { return a + b },
+	Measure:  func(value transfer) int { return value.bytes },
+}
+
+// largest is the summary of a max monoid, which needs an identity below every value.
+type largest struct {
+	value int
+	ok    bool
+}
+
+var maxBytes = Monoid[transfer, largest]{
+	Identity: largest{},
+	Combine: func(a, b largest) largest {
+		if !a.ok || (b.ok && b.value > a.value) {
+			return b
+		}
+		return a
+	},
+	Measure: func(value transfer) largest { return largest{value.bytes, true} },
+}
+
+// checkSummaries fails the test if any node's summary differs from one recalculated from scratch.
+func checkSummaries[T, S comparable](t *testing.T, tree *AugmentedTree[T, S], node *TwoThreeNode[augmentedValue[T, S]]) S {
+	t.Helper()
+	monoid := tree.monoid
+	children := nodeChildren(node)
+	want := monoid.Identity
+	for i, datum := range nodeData(node) {
+		if i < len(children) {
+			want = monoid.Combine(want, checkSummaries(t, tree, children[i]))
+		}
+		want = monoid.Combine(want, monoid.Measure(datum.value))
+	}
+	if len(children) > 0 {
+		want = monoid.Combine(want, checkSummaries(t, tree, children[len(children)-1]))
+	}
+	if got := tree.summaryOf(node); got != want {
+		t.Fatalf("node %v has summary %v, want %v", BFS(node), got, want)
+	}
+	return want
+}
+
+func TestAugmentedTreeAggregate(t *testing.T) {
+	rng := rand.New(rand.NewSource(21))
+	sums := NewAugmentedTree(transferComparator, sumBytes)
+	maxes := NewAugmentedTree(transferComparator, maxBytes)
+	var values []transfer
+
+	for i := 0; i < 2000; i++ {
+		value := transfer{at: rng.Intn(300), bytes: rng.Intn(1000)}
+		if rng.Intn(3) == 0 {
+			found, err := sums.Delete(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := maxes.Delete(value); err != nil {
+				t.Fatal(err)
+			}
+			if found {
+				// Delete removes the first of the equal values it finds, which need not be the first inserted.
+				values = sums.InOrder()
+			}
+		} else {
+			if err := sums.Insert(value); err != nil {
+				t.Fatal(err)
+			}
+			if err := maxes.Insert(value); err != nil {
+				t.Fatal(err)
+			}
+			values = sums.InOrder()
+		}
+
+		// Summaries are only refreshed where nodes changed, so a stale one would show up here.
+		if sums.Len() > 0 {
+			checkSummaries(t, sums, sums.root())
+			checkSummaries(t, maxes, maxes.root())
+		}
+	}
+	if err := sums.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, inclusivity := range []Inclusivity{ExcludeBoth, IncludeLow, IncludeHigh, IncludeBoth} {
+		for lo := -10; lo < 310; lo += 17 {
+			for hi := lo; hi < 320; hi += 23 {
+				wantSum, wantMax := 0, largest{}
+				for _, value := range values {
+					if aboveLow(intComparator, value.at, lo, inclusivity) && belowHigh(intComparator, value.at, hi, inclusivity) {
+						wantSum += value.bytes
+						wantMax = maxBytes.Combine(wantMax, maxBytes.Measure(value))
+					}
+				}
+
+				if got := sums.Aggregate(transfer{at: lo}, transfer{at: hi}, inclusivity); got != wantSum {
+					t.Errorf("sum Aggregate(%d, %d, %d) = %v, want %v", lo, hi, inclusivity, got, wantSum)
+				}
+				if got := maxes.Aggregate(transfer{at: lo}, transfer{at: hi}, inclusivity); got != wantMax {
+					t.Errorf("max Aggregate(%d, %d, %d) = %v, want %v", lo, hi, inclusivity, got, wantMax)
+				}
+			}
+		}
+	}
+}
+
+func TestAugmentedTreeCountsMatchCountRange(t *testing.T) {
+	count := Monoid[int, int]{
+		Identity: 0,
+		Combine:  func(a, b int) int { return a + b },
+		Measure:  func(int) int { return 1 },
+	}
+	tree := NewAugmentedTree(intComparator, count)
+	for i := 0; i < 500; i++ {
+		if err := tree.Insert((i * 37) % 101); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if tree.Total() != tree.Len() {
+		t.Errorf("Total() = %v, want %v", tree.Total(), tree.Len())
+	}
+	for lo := 0; lo < 101; lo += 7 {
+		hi := lo + 30
+		if got, want := tree.Aggregate(lo, hi, IncludeBoth), CountRange(tree.root(), augmentedValue[int, int]{value: lo}, augmentedValue[int, int]{value: hi}, IncludeBoth); got != want {
+			t.Errorf("Aggregate(%d, %d) = %v, want %v", lo, hi, got, want)
+		}
+	}
+}
+
+func TestAugmentedTreeCombinesInOrder(t *testing.T) {
+	concat := Monoid[string, string]{
+		Identity: "",
+		Combine:  func(a, b string) string { return a + b },
+		Measure:  func(value string) string { return value },
+	}
+	tree := NewAugmentedTree(strings.Compare, concat)
+	letters := strings.Split("thequickbrownfxjmpsvlazydg", "")
+	for _, letter := range letters {
+		if err := tree.Insert(letter); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sort.Strings(letters)
+	if got, want := tree.Total(), strings.Join(letters, ""); got != want {
+		t.Errorf("Total() = %v, want %v", got, want)
+	}
+	if got, want := tree.Aggregate("c", "m", IncludeLow), "cdefghijkl"; got != want {
+		t.Errorf("Aggregate() = %v, want %v", got, want)
+	}
+}
+
+func TestAugmentedTreeReplaceRefreshesSummaries(t *testing.T) {
+	tree := NewAugmentedTreeWithPolicy(transferComparator, sumBytes, ReplaceDuplicates)
+	for i := 0; i < 50; i++ {
+		if err := tree.Insert(transfer{at: i, bytes: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tree.Insert(transfer{at: 20, bytes: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := tree.Total(); got != 149 {
+		t.Errorf("Total() = %v, want 149", got)
+	}
+	checkSummaries(t, tree, tree.root())
+}
+
+func TestAugmentedTreeAggregateIsLogarithmic(t *testing.T) {
+	measured := 0
+	count := Monoid[int, int]{
+		Identity: 0,
+		Combine:  func(a, b int) int { return a + b },
+		Measure: func(int) int {
+			measured++
+			return 1
+		},
+	}
+	tree := NewAugmentedTree(intComparator, count)
+	for i := 0; i < 100000; i++ {
+		if err := tree.Insert(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	measured = 0
+	if got := tree.Aggregate(1234, 98765, IncludeBoth); got != 98765-1234+1 {
+		t.Fatalf("Aggregate() = %v, want %v", got, 98765-1234+1)
+	}
+	// Only the values of nodes on the two boundary paths are measured; the rest come from stored summaries.
+	if limit := 4 * tree.Height(); measured > limit {
+		t.Errorf("Aggregate() measured %d values, want at most %d", measured, limit)
+	}
+}
+
+func TestAugmentedTreeEmpty(t *testing.T) {
+	tree := NewAugmentedTree(transferComparator, sumBytes)
+	if tree.Total() != 0 || tree.Aggregate(transfer{at: 0}, transfer{at: 10}, IncludeBoth) != 0 {
+		t.Errorf("empty tree has a non-zero sum")
+	}
+	if err := tree.Insert(transfer{at: 1, bytes: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := tree.Delete(transfer{at: 1}); !found || err != nil || tree.Total() != 0 {
+		t.Errorf("Delete() = %v, %v, and Total() = %v, want 0", found, err, tree.Total())
+	}
+}
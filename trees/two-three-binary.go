@@ -0,0 +1,249 @@
+package trees
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+/* The binary format of a two-three tree is:
+
+	magic     4 bytes  "TT23"
+	version   uint16   binaryVersion
+	count     uint64   the number of elements
+	height    uint32   the height of the encoded tree, 0 when empty
+	elements  count times: a uvarint length, then that many bytes from Codec.Encode, in ascending order
+	checksum  uint32   CRC-32 (IEEE) of everything before it
+
+Fixed-width integers are big-endian. Decoding rebuilds a perfectly balanced tree with bulk loading,
+so the decoded tree may be shorter than the height recorded in the header.
+*/
+
+const (
+	binaryMagic   = "TT23"
+	binaryVersion = 1
+
+	// maxElementLength bounds the length prefix of a single element, so corrupt input cannot force a huge allocation.
+	maxElementLength = 1 << 26
+)
+
+var (
+	// ErrInvalidFormat is returned when decoding input that is not a binary-encoded two-three tree.
+	ErrInvalidFormat = errors.New("not a binary-encoded two-three tree")
+
+	// ErrUnsupportedVersion is returned when decoding a binary format version this package cannot read.
+	ErrUnsupportedVersion = errors.New("unsupported binary format version")
+
+	// ErrChecksum is returned when the checksum of the decoded input does not match the one it holds.
+	ErrChecksum = errors.New("checksum mismatch")
+)
+
+// Codec converts values to and from the bytes stored for each element of the binary format.
+type Codec[T any] interface {
+	// Encode appends the encoding of value to dst, and returns the extended slice.
+	Encode(dst []byte, value T) ([]byte, error)
+
+	// Decode decodes a value from src. src is reused once Decode returns, so it must not be retained.
+	Decode(src []byte) (T, error)
+}
+
+// IntCodec is a Codec for ints, encoded as zig-zag varints.
+type IntCodec struct{}
+
+// Encode appends the value to dst as a zig-zag varint.
+func (IntCodec) Encode(dst []byte, value int) ([]byte, error) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], int64(value))
+	return append(dst, buf[:n]...), nil
+}
+
+// Decode reads a value written by Encode. It returns an error wrapping ErrInvalidFormat if src is not exactly one varint.
+func (IntCodec) Decode(src []byte) (int, error) {
+	value, n := binary.Varint(src)
+	if n <= 0 || n != len(src) {
+		return 0, fmt.Errorf("%w: bad varint", ErrInvalidFormat)
+	}
+	return int(value), nil
+}
+
+// StringCodec is a Codec for strings, stored as their raw bytes.
+type StringCodec struct{}
+
+// Encode appends the value's bytes to dst, unchanged.
+func (StringCodec) Encode(dst []byte, value string) ([]byte, error) {
+	return append(dst, value...), nil
+}
+
+// Decode returns the bytes of src as a string.
+func (StringCodec) Decode(src []byte) (string, error) {
+	return string(src), nil
+}
+
+// binaryHeader is the fixed-size start of the binary format.
+type binaryHeader struct {
+	Magic   [4]byte
+	Version uint16
+	Count   uint64
+	Height  uint32
+}
+
+// Encode writes the tree to w in the binary format, encoding each value with the codec.
+// Values are written in order as the tree is walked, so the tree is never copied.
+// It returns the first error from the codec or from w.
+func Encode[T any](w io.Writer, root *TwoThreeNode[T], codec Codec[T]) error {
+	header := binaryHeader{Version: binaryVersion}
+	copy(header.Magic[:], binaryMagic)
+	if root != nil && root.firstData != nil {
+		header.Count = uint64(root.size)
+		header.Height = uint32(root.height)
+	}
+
+	checksum := crc32.NewIEEE()
+	buffered := bufio.NewWriter(w)
+	out := io.MultiWriter(buffered, checksum)
+	if err := binary.Write(out, binary.BigEndian, header); err != nil {
+		return err
+	}
+
+	var element []byte
+	prefix := make([]byte, binary.MaxVarintLen64)
+	cursor := NewCursor(root)
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		var err error
+		if element, err = codec.Encode(element[:0], cursor.Value()); err != nil {
+			return err
+		}
+		n := binary.PutUvarint(prefix, uint64(len(element)))
+		if _, err := out.Write(prefix[:n]); err != nil {
+			return err
+		}
+		if _, err := out.Write(element); err != nil {
+			return err
+		}
+	}
+
+	if err := binary.Write(buffered, binary.BigEndian, checksum.Sum32()); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// checksumReader passes reads through to r, adding every byte read to the checksum.
+type checksumReader struct {
+	r        *bufio.Reader
+	checksum hash.Hash32
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.checksum.Write(p[:n])
+	return n, err
+}
+
+func (c *checksumReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.checksum.Write([]byte{b})
+	}
+	return b, err
+}
+
+// unexpectedEOF turns io.EOF into io.ErrUnexpectedEOF, as the input ended before the format did.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Decode reads a tree written by Encode from r, decoding each value with the codec and ordering them by the comparator.
+// Values are read straight into the slice the tree is bulk loaded from, so they are held in memory once.
+// r is read through a buffer, so Decode may consume bytes beyond the end of the encoding.
+// It returns ErrInvalidFormat, ErrUnsupportedVersion or ErrChecksum (possibly wrapped) for input it cannot read,
+// io.ErrUnexpectedEOF if the input is truncated, an error wrapping ErrNotSorted if the values are out of order,
+// or the first error from the codec or from r.
+func Decode[T any](r io.Reader, comparator func(a, b T) int, codec Codec[T]) (*TwoThreeTree[T], error) {
+	values, err := decodeValues(r, comparator, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := NewTwoThreeTree(comparator)
+	tree.root = buildSorted(values, comparator, AllowDuplicates)
+	tree.size = len(values)
+	return tree, nil
+}
+
+// decodeValues reads the values of a tree in the binary format from r, in ascending order. See Decode.
+func decodeValues[T any](r io.Reader, comparator func(a, b T) int, codec Codec[T]) ([]T, error) {
+	in := &checksumReader{r: bufio.NewReader(r), checksum: crc32.NewIEEE()}
+
+	var header binaryHeader
+	if err := binary.Read(in, binary.BigEndian, &header); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if string(header.Magic[:]) != binaryMagic {
+		return nil, ErrInvalidFormat
+	}
+	if header.Version != binaryVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, header.Version)
+	}
+
+	// The count is not trusted for the allocation until the elements are actually read.
+	values := make([]T, 0, minInt(header.Count, 1<<16))
+	var element []byte
+	for i := uint64(0); i < header.Count; i++ {
+		length, err := binary.ReadUvarint(in)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		if length > maxElementLength {
+			return nil, fmt.Errorf("%w: element %d is %d bytes long", ErrInvalidFormat, i, length)
+		}
+
+		if uint64(cap(element)) < length {
+			element = make([]byte, length)
+		}
+		element = element[:length]
+		if _, err := io.ReadFull(in, element); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		value, err := codec.Decode(element)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(values); n > 0 && comparator(values[n-1], value) > 0 {
+			return nil, fmt.Errorf("%w: %v at index %d follows %v", ErrNotSorted, value, n, values[n-1])
+		}
+		values = append(values, value)
+	}
+
+	want := in.checksum.Sum32()
+	var got uint32
+	if err := binary.Read(in.r, binary.BigEndian, &got); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if got != want {
+		return nil, ErrChecksum
+	}
+
+	return values, nil
+}
+
+// minInt returns the smaller of a count and a limit.
+func minInt(count uint64, limit int) int {
+	if count < uint64(limit) {
+		return int(count)
+	}
+	return limit
+}
+
+// Encode writes the tree to w in the binary format, encoding each value with the codec. See Encode.
+func (tree *TwoThreeTree[T]) Encode(w io.Writer, codec Codec[T]) error {
+	return Encode(w, tree.root, codec)
+}
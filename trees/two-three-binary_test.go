@@ -0,0 +1,201 @@
+package trees
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func encodeInts(t *testing.T, values ...int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Encode[int](&buf, buildIntTree(t, values...), IntCodec{}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestEncodeFormat(t *testing.T) {
+	got := encodeInts(t, 2, 1, -1)
+	want := []byte{
+		'T', 'T', '2', '3', // magic
+		0, 1, // version
+		0, 0, 0, 0, 0, 0, 0, 3, // count
+		0, 0, 0, 2, // height
+		1, 1, // -1
+		1, 2, // 1
+		1, 4, // 2
+	}
+	if !bytes.Equal(got[:len(got)-4], want) {
+		t.Errorf("Encode() = %v, want %v followed by a checksum", got, want)
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 10, 1000} {
+		t.Run(fmt.Sprintf("It round trips %d values", n), func(t *testing.T) {
+			tree := NewTwoThreeTree(intComparator)
+			for i := 0; i < n; i++ {
+				if err := tree.Insert((i*7919)%n - n/2); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var buf bytes.Buffer
+			if err := tree.Encode(&buf, IntCodec{}); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			decoded, err := Decode[int](&buf, intComparator, IntCodec{})
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if got, want := InOrder(decoded.Root()), InOrder(tree.Root()); !reflect.DeepEqual(got, want) {
+				t.Errorf("Decode() = %v, want %v", got, want)
+			}
+			if err := decoded.Validate(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestBinaryRoundTripStrings(t *testing.T) {
+	tree := NewTwoThreeTree(strings.Compare)
+	for _, word := range strings.Fields("the quick brown fox jumps over the lazy dog") {
+		if err := tree.Insert(word); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tree.Insert(""); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tree.Encode(&buf, StringCodec{}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	decoded, err := Decode[string](&buf, strings.Compare, StringCodec{})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got, want := InOrder(decoded.Root()), InOrder(tree.Root()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	valid := encodeInts(t, 1, 2, 3, 4, 5)
+	modify := func(fn func(data []byte) []byte) []byte {
+		return fn(append([]byte(nil), valid...))
+	}
+	descending := func(a, b int) int { return intComparator(b, a) }
+	reversedTree := NewTwoThreeTree(descending)
+	for _, value := range []int{1, 2, 3} {
+		if err := reversedTree.Insert(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var reversed bytes.Buffer
+	if err := reversedTree.Encode(&reversed, IntCodec{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{
+			name:    "It rejects the wrong magic",
+			data:    modify(func(data []byte) []byte { data[0] = 'X'; return data }),
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name:    "It rejects an unknown version",
+			data:    modify(func(data []byte) []byte { data[5] = 9; return data }),
+			wantErr: ErrUnsupportedVersion,
+		},
+		{
+			name:    "It detects a corrupted element",
+			data:    modify(func(data []byte) []byte { data[len(data)-5] ^= 0x10; return data }),
+			wantErr: ErrChecksum,
+		},
+		{
+			name:    "It detects a corrupted checksum",
+			data:    modify(func(data []byte) []byte { data[len(data)-1] ^= 1; return data }),
+			wantErr: ErrChecksum,
+		},
+		{
+			name:    "It rejects an oversized element",
+			data:    modify(func(data []byte) []byte { return append(data[:18], 0xff, 0xff, 0xff, 0xff, 0x0f) }),
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name:    "It rejects values out of order for the comparator",
+			data:    reversed.Bytes(),
+			wantErr: ErrNotSorted,
+		},
+		{
+			name:    "It reports empty input as truncated",
+			data:    nil,
+			wantErr: io.ErrUnexpectedEOF,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode[int](bytes.NewReader(tt.data), intComparator, IntCodec{}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	data := encodeInts(t, 10, 20, 30, 40, 50, 60)
+	for n := 0; n < len(data); n++ {
+		if _, err := Decode[int](bytes.NewReader(data[:n]), intComparator, IntCodec{}); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Decode() of %d of %d bytes: error = %v, want %v", n, len(data), err, io.ErrUnexpectedEOF)
+		}
+	}
+}
+
+// failingCodec fails to encode or decode the value bad.
+type failingCodec struct {
+	IntCodec
+	bad int
+}
+
+var errCodec = errors.New("codec failed")
+
+func (c failingCodec) Encode(dst []byte, value int) ([]byte, error) {
+	if value == c.bad {
+		return dst, errCodec
+	}
+	return c.IntCodec.Encode(dst, value)
+}
+
+func (c failingCodec) Decode(src []byte) (int, error) {
+	value, err := c.IntCodec.Decode(src)
+	if err == nil && value == c.bad {
+		return 0, errCodec
+	}
+	return value, err
+}
+
+func TestBinaryPropagatesErrors(t *testing.T) {
+	root := buildIntTree(t, 1, 2, 3)
+	if err := Encode[int](io.Discard, root, failingCodec{bad: 2}); !errors.Is(err, errCodec) {
+		t.Errorf("Encode() error = %v, want %v", err, errCodec)
+	}
+	if err := Encode[int](failingWriter{}, root, IntCodec{}); !errors.Is(err, errWriteFailed) {
+		t.Errorf("Encode() error = %v, want %v", err, errWriteFailed)
+	}
+	if _, err := Decode[int](bytes.NewReader(encodeInts(t, 1, 2, 3)), intComparator, failingCodec{bad: 2}); !errors.Is(err, errCodec) {
+		t.Errorf("Decode() error = %v, want %v", err, errCodec)
+	}
+}
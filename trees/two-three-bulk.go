@@ -0,0 +1,141 @@
+package trees
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotSorted is returned when bulk loading from values that are not in ascending order.
+var ErrNotSorted = errors.New("values are not sorted")
+
+// checkSorted returns an error wrapping ErrNotSorted if the values are not in ascending order.
+func checkSorted[T any](values []T, comparator func(a, b T) int) error {
+	for i := 1; i < len(values); i++ {
+		if comparator(values[i-1], values[i]) > 0 {
+			return fmt.Errorf("%w: %v at index %d follows %v", ErrNotSorted, values[i], i, values[i-1])
+		}
+	}
+	return nil
+}
+
+// buildBalanced builds a subtree of the given height holding the sorted values.
+// The number of values must be between 2^height - 1 and 3^height - 1.
+// Children are built before their parent, so each value is visited once.
+func buildBalanced[T any](values []T, height int, capacities []int, comparator func(a, b T) int, duplicates DuplicatePolicy) *TwoThreeNode[T] {
+	node := &TwoThreeNode[T]{
+		comparator: comparator,
+		duplicates: duplicates,
+		height:     height,
+		size:       len(values),
+	}
+
+	if height == 1 {
+		data := make([]*T, len(values))
+		for i := range values {
+			data[i] = &values[i]
+		}
+		setNodeData(node, data)
+		return node
+	}
+
+	// Use a 2-node if two full children can hold the values, and a 3-node otherwise.
+	childCapacity := capacities[height-1]
+	k := 2
+	if len(values) > 2*childCapacity+1 {
+		k = 3
+	}
+
+	// Spread the values that are not separators evenly across the children.
+	remaining := len(values) - (k - 1)
+	var data []*T
+	var children []*TwoThreeNode[T]
+	start := 0
+	for i := 0; i < k; i++ {
+		size := remaining / k
+		if i < remaining%k {
+			size++
+		}
+		children = append(children, buildBalanced(values[start:start+size], height-1, capacities, comparator, duplicates))
+		start += size
+		if i < k-1 {
+			data = append(data, &values[start])
+			start++
+		}
+	}
+
+	setNodeData(node, data)
+	setNodeChildren(node, children)
+	return node
+}
+
+// buildSorted builds a perfectly balanced two-three tree from sorted values owned by the tree,
+// which handles duplicate values according to the given policy.
+// It returns the root node of the tree, or nil when there are no values.
+func buildSorted[T any](values []T, comparator func(a, b T) int, duplicates DuplicatePolicy) *TwoThreeNode[T] {
+	if len(values) == 0 {
+		return nil
+	}
+
+	// capacities[h] is the most values a tree of height h can hold: 3^h - 1.
+	capacities := []int{0}
+	for capacities[len(capacities)-1] < len(values) {
+		capacities = append(capacities, 3*capacities[len(capacities)-1]+2)
+	}
+
+	return buildBalanced(values, len(capacities)-1, capacities, comparator, duplicates)
+}
+
+// FromSorted builds a perfectly balanced two-three tree from values sorted in ascending order by the comparator.
+// It runs in linear time, rather than descending and splitting once per value as repeated calls to Insert do.
+// The values are copied, so the slice may be reused afterwards.
+// It returns the root node of the tree, which is nil when there are no values,
+// or an error wrapping ErrNotSorted if the values are out of order.
+func FromSorted[T any](values []T, comparator func(a, b T) int) (*TwoThreeNode[T], error) {
+	if err := checkSorted(values, comparator); err != nil {
+		return nil, err
+	}
+	return buildSorted(append([]T(nil), values...), comparator, AllowDuplicates), nil
+}
+
+// FromSortedFunc builds a perfectly balanced two-three tree from the values returned by next,
+// which returns false once there are no more values.
+// The values must be in ascending order by the comparator, and are checked as they are read.
+// It returns the root node of the tree, which is nil when there are no values,
+// or an error wrapping ErrNotSorted if the values are out of order.
+func FromSortedFunc[T any](next func() (T, bool), comparator func(a, b T) int) (*TwoThreeNode[T], error) {
+	var values []T
+	for value, ok := next(); ok; value, ok = next() {
+		if n := len(values); n > 0 && comparator(values[n-1], value) > 0 {
+			return nil, fmt.Errorf("%w: %v at index %d follows %v", ErrNotSorted, value, n, values[n-1])
+		}
+		values = append(values, value)
+	}
+	return buildSorted(values, comparator, AllowDuplicates), nil
+}
+
+// FromSortedChan builds a perfectly balanced two-three tree from the values received on the channel until it is closed.
+// The values must be in ascending order by the comparator.
+// If they are not, FromSortedChan stops receiving, and the channel is left undrained.
+// It returns the root node of the tree, which is nil when there are no values,
+// or an error wrapping ErrNotSorted if the values are out of order.
+func FromSortedChan[T any](values <-chan T, comparator func(a, b T) int) (*TwoThreeNode[T], error) {
+	return FromSortedFunc(func() (T, bool) {
+		value, ok := <-values
+		return value, ok
+	}, comparator)
+}
+
+// NewTwoThreeTreeFromSorted is a constructor for a two-three tree holding values sorted in ascending order by the comparator.
+// The tree allows duplicate values.
+// It returns an error wrapping ErrNotSorted if the values are out of order.
+func NewTwoThreeTreeFromSorted[T any](values []T, comparator func(a, b T) int) (*TwoThreeTree[T], error) {
+	root, err := FromSorted(values, comparator)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := NewTwoThreeTree(comparator)
+	tree.root = root
+	tree.size = len(values)
+	return tree, nil
+}
@@ -0,0 +1,114 @@
+package trees
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFromSorted(t *testing.T) {
+	for n := 0; n <= 300; n++ {
+		values := make([]int, n)
+		for i := range values {
+			values[i] = i / 2
+		}
+
+		root, err := FromSorted(values, intComparator)
+		if err != nil {
+			t.Fatalf("FromSorted(%d values) error = %v", n, err)
+		}
+		if n == 0 {
+			if root != nil {
+				t.Fatalf("FromSorted(no values) = %v, want nil", ToString(root))
+			}
+			continue
+		}
+
+		checkShape(t, root)
+		if got := InOrder(root); !reflect.DeepEqual(got, values) {
+			t.Fatalf("FromSorted(%d values) holds %v", n, got)
+		}
+	}
+}
+
+func TestFromSortedCopiesValues(t *testing.T) {
+	values := []int{1, 2, 3}
+	root, _ := FromSorted(values, intComparator)
+	values[0] = 100
+
+	if got := InOrder(root); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("InOrder() = %v, want [1 2 3]", got)
+	}
+}
+
+func TestFromSortedNotSorted(t *testing.T) {
+	if _, err := FromSorted([]int{1, 3, 2}, intComparator); !errors.Is(err, ErrNotSorted) {
+		t.Errorf("FromSorted() error = %v, want ErrNotSorted", err)
+	}
+
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 3
+	ch <- 2
+	close(ch)
+	if _, err := FromSortedChan(ch, intComparator); !errors.Is(err, ErrNotSorted) {
+		t.Errorf("FromSortedChan() error = %v, want ErrNotSorted", err)
+	}
+}
+
+func TestFromSortedChan(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		for i := 0; i < 1000; i++ {
+			ch <- i
+		}
+		close(ch)
+	}()
+
+	root, err := FromSortedChan(ch, intComparator)
+	if err != nil {
+		t.Fatalf("FromSortedChan() error = %v", err)
+	}
+	checkShape(t, root)
+	if got := InOrder(root); len(got) != 1000 || got[0] != 0 || got[999] != 999 {
+		t.Errorf("FromSortedChan() holds %d values", len(got))
+	}
+
+	// The bulk-loaded tree keeps working with Insert and Delete.
+	root, _ = Insert(root, 500)
+	root, _, _ = Delete(root, 0)
+	checkShape(t, root)
+}
+
+func TestNewTwoThreeTreeFromSorted(t *testing.T) {
+	tree, err := NewTwoThreeTreeFromSorted([]int{1, 2, 3, 4, 5}, intComparator)
+	if err != nil {
+		t.Fatalf("NewTwoThreeTreeFromSorted() error = %v", err)
+	}
+	if err := tree.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if got := tree.Len(); got != 5 {
+		t.Errorf("Len() = %v, want 5", got)
+	}
+}
+
+func BenchmarkFromSorted(b *testing.B) {
+	values := make([]int, 100000)
+	for i := range values {
+		values[i] = i
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FromSorted(values, intComparator)
+	}
+}
+
+func BenchmarkInsertSorted(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		root := New(0, intComparator)
+		for v := 1; v < 100000; v++ {
+			root, _ = Insert(root, v)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package trees
+
+// InOrder traverses the tree in ascending order.
+// It returns the values of the tree, sorted by the comparator.
+func InOrder[T any](root *TwoThreeNode[T]) []T {
+	var result []T
+	if root == nil {
+		return result
+	}
+
+	children := nodeChildren(root)
+	for i, datum := range nodeData(root) {
+		if i < len(children) {
+			result = append(result, InOrder(children[i])...)
+		}
+		result = append(result, *datum)
+	}
+	if len(children) > 0 {
+		result = append(result, InOrder(children[len(children)-1])...)
+	}
+	return result
+}
+
+// ReverseOrder traverses the tree in descending order.
+// It returns the values of the tree, sorted in reverse by the comparator.
+func ReverseOrder[T any](root *TwoThreeNode[T]) []T {
+	var result []T
+	if root == nil {
+		return result
+	}
+
+	children := nodeChildren(root)
+	data := nodeData(root)
+	if len(children) > 0 {
+		result = append(result, ReverseOrder(children[len(children)-1])...)
+	}
+	for i := len(data) - 1; i >= 0; i-- {
+		result = append(result, *data[i])
+		if i < len(children) {
+			result = append(result, ReverseOrder(children[i])...)
+		}
+	}
+	return result
+}
+
+// rightmostLeaf returns the leaf holding the largest value of the given subtree.
+func rightmostLeaf[T any](node *TwoThreeNode[T]) *TwoThreeNode[T] {
+	for !isLeaf(*node) {
+		children := nodeChildren(node)
+		node = children[len(children)-1]
+	}
+	return node
+}
+
+// Cursor is a position within a two-three tree that can step through its values in either order.
+// Stepping follows the parent pointers of the nodes, so it never re-descends from the root.
+// A cursor is invalidated by any change to the tree it was created from.
+type Cursor[T any] struct {
+	root *TwoThreeNode[T]
+
+	// node and index identify the datum the cursor is positioned at.
+	// node is nil when the cursor is not positioned at a value.
+	node  *TwoThreeNode[T]
+	index int
+}
+
+// NewCursor is a constructor for a cursor over the tree with the given root.
+// The cursor is not positioned at a value until First, Last or Seek is called.
+func NewCursor[T any](root *TwoThreeNode[T]) *Cursor[T] {
+	return &Cursor[T]{
+		root:  root,
+		node:  nil,
+		index: 0,
+	}
+}
+
+// Cursor returns a new cursor over the tree.
+func (tree *TwoThreeTree[T]) Cursor() *Cursor[T] {
+	return NewCursor(tree.root)
+}
+
+// isEmpty reports whether the cursor's tree holds no values.
+func (cursor *Cursor[T]) isEmpty() bool {
+	return cursor.root == nil || cursor.root.firstData == nil
+}
+
+// Valid reports whether the cursor is positioned at a value.
+func (cursor *Cursor[T]) Valid() bool {
+	return cursor.node != nil
+}
+
+// Value returns the value the cursor is positioned at.
+// It panics if the cursor is not valid.
+func (cursor *Cursor[T]) Value() T {
+	return *nodeData(cursor.node)[cursor.index]
+}
+
+// First positions the cursor at the smallest value of the tree.
+// It returns whether the cursor is valid.
+func (cursor *Cursor[T]) First() bool {
+	cursor.node = nil
+	if !cursor.isEmpty() {
+		cursor.node, cursor.index = leftmostLeaf(cursor.root), 0
+	}
+	return cursor.Valid()
+}
+
+// Last positions the cursor at the largest value of the tree.
+// It returns whether the cursor is valid.
+func (cursor *Cursor[T]) Last() bool {
+	cursor.node = nil
+	if !cursor.isEmpty() {
+		cursor.node = rightmostLeaf(cursor.root)
+		cursor.index = datumCount(cursor.node) - 1
+	}
+	return cursor.Valid()
+}
+
+// Seek positions the cursor at the smallest value of the tree that is greater than or equal to the given value.
+// It returns whether the cursor is valid, which is false when every value is less than the given one.
+func (cursor *Cursor[T]) Seek(value T) bool {
+	cursor.node = nil
+	if cursor.isEmpty() {
+		return false
+	}
+
+	node := cursor.root
+	for node != nil {
+		data := nodeData(node)
+		children := nodeChildren(node)
+		next := len(data)
+		for i, datum := range data {
+			if node.comparator(value, *datum) <= 0 {
+				cursor.node, cursor.index = node, i
+				next = i
+				break
+			}
+		}
+		if len(children) == 0 {
+			break
+		}
+		node = children[next]
+	}
+	return cursor.Valid()
+}
+
+// Next moves the cursor to the next larger value.
+// It returns whether the cursor is valid, which is false once it moves past the largest value.
+func (cursor *Cursor[T]) Next() bool {
+	if !cursor.Valid() {
+		return false
+	}
+
+	node := cursor.node
+	if !isLeaf(*node) {
+		cursor.node, cursor.index = leftmostLeaf(nodeChildren(node)[cursor.index+1]), 0
+		return true
+	}
+
+	if cursor.index+1 < datumCount(node) {
+		cursor.index++
+		return true
+	}
+
+	// Climb until node is reached through a child that has a separator to its right.
+	for node.parent != nil {
+		parent := node.parent
+		if pos := indexOfChild(parent, node); pos < datumCount(parent) {
+			cursor.node, cursor.index = parent, pos
+			return true
+		}
+		node = parent
+	}
+
+	cursor.node = nil
+	return false
+}
+
+// Prev moves the cursor to the next smaller value.
+// It returns whether the cursor is valid, which is false once it moves past the smallest value.
+func (cursor *Cursor[T]) Prev() bool {
+	if !cursor.Valid() {
+		return false
+	}
+
+	node := cursor.node
+	if !isLeaf(*node) {
+		leaf := rightmostLeaf(nodeChildren(node)[cursor.index])
+		cursor.node, cursor.index = leaf, datumCount(leaf)-1
+		return true
+	}
+
+	if cursor.index > 0 {
+		cursor.index--
+		return true
+	}
+
+	// Climb until node is reached through a child that has a separator to its left.
+	for node.parent != nil {
+		parent := node.parent
+		if pos := indexOfChild(parent, node); pos > 0 {
+			cursor.node, cursor.index = parent, pos-1
+			return true
+		}
+		node = parent
+	}
+
+	cursor.node = nil
+	return false
+}
@@ -0,0 +1,127 @@
+package trees
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestInOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		root *TwoThreeNode[int]
+		want []int
+	}{
+		{
+			name: "It returns the values in ascending order",
+			root: buildThreeLevelTree(),
+			want: []int{5, 7, 8, 10, 15, 17, 20, 25, 35, 40, 45},
+		},
+		{
+			name: "It returns no values for an empty tree",
+			root: nil,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InOrder(tt.root); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReverseOrder(t *testing.T) {
+	want := []int{45, 40, 35, 25, 20, 17, 15, 10, 8, 7, 5}
+	if got := ReverseOrder(buildThreeLevelTree()); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReverseOrder() = %v, want %v", got, want)
+	}
+}
+
+func TestCursor(t *testing.T) {
+	root := buildThreeLevelTree()
+	want := InOrder(root)
+
+	t.Run("It steps forwards through every value", func(t *testing.T) {
+		var got []int
+		cursor := NewCursor(root)
+		for ok := cursor.First(); ok; ok = cursor.Next() {
+			got = append(got, cursor.Value())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Next() visited %v, want %v", got, want)
+		}
+	})
+
+	t.Run("It steps backwards through every value", func(t *testing.T) {
+		var got []int
+		cursor := NewCursor(root)
+		for ok := cursor.Last(); ok; ok = cursor.Prev() {
+			got = append(got, cursor.Value())
+		}
+		if want := ReverseOrder(root); !reflect.DeepEqual(got, want) {
+			t.Errorf("Prev() visited %v, want %v", got, want)
+		}
+	})
+
+	seekTests := []struct {
+		name      string
+		value     int
+		want      int
+		wantValid bool
+	}{
+		{name: "It seeks to an exact match in a leaf", value: 15, want: 15, wantValid: true},
+		{name: "It seeks to an exact match in an internal node", value: 25, want: 25, wantValid: true},
+		{name: "It seeks to the next larger value", value: 21, want: 25, wantValid: true},
+		{name: "It seeks to the smallest value", value: 0, want: 5, wantValid: true},
+		{name: "It is invalid past the largest value", value: 46, wantValid: false},
+	}
+	for _, tt := range seekTests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := NewCursor(root)
+			if valid := cursor.Seek(tt.value); valid != tt.wantValid {
+				t.Fatalf("Seek() = %v, want %v", valid, tt.wantValid)
+			}
+			if tt.wantValid && cursor.Value() != tt.want {
+				t.Errorf("Seek() positioned at %v, want %v", cursor.Value(), tt.want)
+			}
+		})
+	}
+
+	t.Run("It steps in both directions from a seek", func(t *testing.T) {
+		cursor := NewCursor(root)
+		cursor.Seek(10)
+		if !cursor.Prev() || cursor.Value() != 8 {
+			t.Errorf("Prev() positioned at %v, want 8", cursor.Value())
+		}
+		if !cursor.Next() || !cursor.Next() || cursor.Value() != 15 {
+			t.Errorf("Next() positioned at %v, want 15", cursor.Value())
+		}
+	})
+
+	t.Run("It visits every value of a larger tree in both directions", func(t *testing.T) {
+		large := buildIntTree(t, rand.New(rand.NewSource(1)).Perm(100)...)
+		cursor := NewCursor(large)
+		count := 0
+		for ok := cursor.First(); ok; ok = cursor.Next() {
+			if cursor.Value() != count {
+				t.Fatalf("Next() positioned at %v, want %v", cursor.Value(), count)
+			}
+			count++
+		}
+		for ok := cursor.Last(); ok; ok = cursor.Prev() {
+			count--
+			if cursor.Value() != count {
+				t.Fatalf("Prev() positioned at %v, want %v", cursor.Value(), count)
+			}
+		}
+	})
+
+	t.Run("It is invalid on an empty tree", func(t *testing.T) {
+		cursor := NewTwoThreeTree(intComparator).Cursor()
+		if cursor.First() || cursor.Last() || cursor.Seek(1) || cursor.Valid() {
+			t.Errorf("Cursor on an empty tree is valid")
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package trees
+
+import "errors"
+
+// findNode locates the node of the given twoThreeTree that holds the value.
+// It returns the node and the index (0 or 1) of the matching datum within it.
+// If no node holds the value, it returns nil.
+func findNode[T any](node *TwoThreeNode[T], value T) (*TwoThreeNode[T], int, error) {
+	for i, datum := range nodeData(node) {
+		if node.comparator(value, *datum) == 0 {
+			return node, i, nil
+		}
+	}
+
+	if isLeaf(*node) {
+		return nil, 0, nil
+	}
+
+	nt, err := nodeType(*node)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch nt {
+	case twoNode:
+		if node.comparator(value, *node.firstData) < 0 {
+			return findNode(node.firstChild, value)
+		} else {
+			return findNode(node.secondChild, value)
+		}
+	case threeNode:
+		if node.comparator(value, *node.firstData) < 0 {
+			return findNode(node.firstChild, value)
+		} else if node.comparator(value, *node.secondData) < 0 {
+			return findNode(node.secondChild, value)
+		} else {
+			return findNode(node.thirdChild, value)
+		}
+	}
+
+	return nil, 0, errors.New("Unknown node type")
+}
+
+// leftmostLeaf returns the leaf holding the smallest value of the given subtree.
+func leftmostLeaf[T any](node *TwoThreeNode[T]) *TwoThreeNode[T] {
+	for node.firstChild != nil {
+		node = node.firstChild
+	}
+	return node
+}
+
+// indexOfChild returns the position of child among the children of parent, or -1.
+func indexOfChild[T any](parent, child *TwoThreeNode[T]) int {
+	for i, c := range nodeChildren(parent) {
+		if c == child {
+			return i
+		}
+	}
+	return -1
+}
+
+// Delete removes a value from the tree.
+// Note that the root of the tree may be modified by this operation, and is nil once the last value is removed.
+// It returns the root node of the tree, and whether the value was found.
+func Delete[T any](root *TwoThreeNode[T], value T) (*TwoThreeNode[T], bool, error) {
+	return deleteValue(twoThreeLayout[T]{}, root, value)
+}
+
+// deleteValue removes a value from the tree through the given layout.
+// It returns the root node of the tree, and whether the value was found.
+func deleteValue[T any](layout twoThreeLayout[T], root *TwoThreeNode[T], value T) (*TwoThreeNode[T], bool, error) {
+	if root == nil {
+		return nil, false, nil
+	}
+
+	node, idx, err := findNode(root, value)
+	if err != nil {
+		return root, false, err
+	}
+	if node == nil {
+		return root, false, nil
+	}
+
+	return layout.remove(node, idx), true, nil
+}
+
+// removeDatum removes the datum at idx from the given node. The tree is balanced as a B-tree of order 3:
+// an internal datum is replaced by its in-order successor, which is removed from its leaf instead,
+// and a node left without data borrows from or merges with a sibling, and so on up the tree.
+// It returns the new root of the tree, which is nil once the last value is removed.
+func removeDatum[T any](node *TwoThreeNode[T], idx int) *TwoThreeNode[T] {
+	return twoThreeLayout[T]{}.remove(node, idx)
+}
+
+// remove does what removeDatum does, calling the layout's onUpdate on every node it changes.
+func (layout twoThreeLayout[T]) remove(node *TwoThreeNode[T], idx int) *TwoThreeNode[T] {
+	root := removePath[*T, *TwoThreeNode[T]](layout, 3, pathTo(node), idx)
+	if root != nil {
+		root.parent = nil
+	}
+	return root
+}
@@ -0,0 +1,143 @@
+package trees
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// checkShape fails the test if the tree breaks any structural invariant.
+func checkShape[T any](t *testing.T, root *TwoThreeNode[T]) {
+	t.Helper()
+	if err := Validate(root); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func buildIntTree(t *testing.T, values ...int) *TwoThreeNode[int] {
+	t.Helper()
+	root := New(values[0], intComparator)
+	for _, value := range values[1:] {
+		var err error
+		if root, err = Insert(root, value); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+	return root
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		root  *TwoThreeNode[int]
+		value int
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      []int
+		wantFound bool
+	}{
+		{
+			name: "It removes a value from a leaf with two values",
+			args: args{
+				root:  ttni().setFD(1).setSD(2),
+				value: 1,
+			},
+			want:      []int{2},
+			wantFound: true,
+		},
+		{
+			name: "It returns false when the value is not in the tree",
+			args: args{
+				root:  buildThreeLevelTree(),
+				value: 6,
+			},
+			want:      []int{10, 25, 7, 17, 40, 5, 8, 15, 20, 35, 45},
+			wantFound: false,
+		},
+		{
+			name: "It borrows from a sibling with two values",
+			args: args{
+				root:  ttni().setFD(10).setFC(ttni().setFD(5)).setSC(ttni().setFD(15).setSD(20)),
+				value: 5,
+			},
+			want:      []int{15, 10, 20},
+			wantFound: true,
+		},
+		{
+			name: "It merges with a sibling and shrinks the root",
+			args: args{
+				root:  ttni().setFD(10).setFC(ttni().setFD(5)).setSC(ttni().setFD(15)),
+				value: 15,
+			},
+			want:      []int{5, 10},
+			wantFound: true,
+		},
+		{
+			name: "It replaces an internal value with its successor",
+			args: args{
+				root:  buildThreeLevelTree(),
+				value: 25,
+			},
+			want:      []int{10, 7, 17, 35, 5, 8, 15, 20, 40, 45},
+			wantFound: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := Delete(tt.args.root, tt.args.value)
+			if err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if found != tt.wantFound {
+				t.Errorf("Delete() found = %v, want %v", found, tt.wantFound)
+			}
+			if data := BFS(got); !reflect.DeepEqual(data, tt.want) {
+				t.Errorf("Delete() = %v, want %v\n%v", data, tt.want, Print(got))
+			}
+		})
+	}
+}
+
+func TestDeleteLastValue(t *testing.T) {
+	got, found, err := Delete(ttni().setFD(1), 1)
+	if err != nil || !found || got != nil {
+		t.Errorf("Delete() = %v, %v, %v, want nil, true, nil", got, found, err)
+	}
+}
+
+func TestDeleteAll(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	values := r.Perm(200)
+	root := buildIntTree(t, values...)
+
+	remaining := append([]int{}, values...)
+	sort.Ints(remaining)
+
+	for _, value := range r.Perm(200) {
+		var found bool
+		var err error
+		root, found, err = Delete(root, value)
+		if err != nil || !found {
+			t.Fatalf("Delete(%d) = %v, %v, want true, nil", value, found, err)
+		}
+
+		idx := sort.SearchInts(remaining, value)
+		remaining = append(remaining[:idx], remaining[idx+1:]...)
+
+		if len(remaining) == 0 {
+			if root != nil {
+				t.Fatalf("Delete() root = %v, want nil", ToString(root))
+			}
+			break
+		}
+		if root.parent != nil {
+			t.Fatalf("Delete() root has a parent")
+		}
+		checkShape(t, root)
+		if got := InOrder(root); !reflect.DeepEqual(got, remaining) {
+			t.Fatalf("Delete(%d) left %v, want %v", value, got, remaining)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package trees
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DOTOptions controls what WriteDOT draws.
+type DOTOptions[T any] struct {
+	// Name is the name of the graph, "TwoThreeTree" when empty.
+	Name string
+
+	// ParentEdges adds a dashed edge from every child back to its parent, to check the parent pointers.
+	ParentEdges bool
+
+	// Heights adds the height of each node below its values.
+	Heights bool
+
+	// Highlight lists nodes to fill in, such as the path returned by LookupPath.
+	Highlight []*TwoThreeNode[T]
+}
+
+// dotEscaper escapes the characters that have a meaning within a record label.
+var dotEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`|`, `\|`,
+	`<`, `\<`,
+	`>`, `\>`,
+	"\n", `\n`,
+)
+
+// dotWriter writes to w until a write fails, and then remembers the error.
+type dotWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (d *dotWriter) printf(format string, args ...any) {
+	if d.err == nil {
+		_, d.err = fmt.Fprintf(d.w, format, args...)
+	}
+}
+
+// dotLabel returns the record label of a node: a port for each child, separated by the node's values.
+// An internal 2-node reads "<c0>|10|<c1>", and a 3-node "<c0>|10|<c1>|25|<c2>".
+// A leaf has no children, so its label holds only its values, such as "10|25".
+func dotLabel[T any](node *TwoThreeNode[T], heights bool) string {
+	var fields []string
+	ports := !isLeaf(*node)
+	data := nodeData(node)
+	for i, datum := range data {
+		if ports {
+			fields = append(fields, fmt.Sprintf("<c%d>", i))
+		}
+		fields = append(fields, dotEscaper.Replace(fmt.Sprintf("%v", *datum)))
+	}
+	if ports {
+		fields = append(fields, fmt.Sprintf("<c%d>", len(data)))
+	}
+
+	label := strings.Join(fields, "|")
+	if heights {
+		label = fmt.Sprintf("{{%s}|h: %d}", label, node.height)
+	}
+	return label
+}
+
+// WriteDOT writes the tree as a Graphviz DOT digraph, with each node drawn as a record
+// whose ports lead to its children. Nodes are named n0, n1, ... in breadth-first order,
+// so the output for a given tree shape is always the same.
+// It returns the first error returned by w.
+func WriteDOT[T any](w io.Writer, root *TwoThreeNode[T], opts DOTOptions[T]) error {
+	name := opts.Name
+	if name == "" {
+		name = "TwoThreeTree"
+	}
+
+	highlighted := make(map[*TwoThreeNode[T]]bool, len(opts.Highlight))
+	for _, node := range opts.Highlight {
+		highlighted[node] = true
+	}
+
+	d := &dotWriter{w: w}
+	d.printf("digraph %q {\n", name)
+	d.printf("\tnode [shape=record];\n")
+
+	if root != nil && (root.firstData != nil || !isLeaf(*root)) {
+		ids := map[*TwoThreeNode[T]]string{}
+		queue := []*TwoThreeNode[T]{root}
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			ids[node] = fmt.Sprintf("n%d", len(ids))
+			queue = append(queue, nodeChildren(node)...)
+		}
+
+		queue = []*TwoThreeNode[T]{root}
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+
+			style := ""
+			if highlighted[node] {
+				style = ", style=filled, fillcolor=gold"
+			}
+			d.printf("\t%s [label=\"%s\"%s];\n", ids[node], dotLabel(node, opts.Heights), style)
+
+			for i, child := range nodeChildren(node) {
+				d.printf("\t%s:c%d -> %s;\n", ids[node], i, ids[child])
+				if opts.ParentEdges && child.parent != nil {
+					// A parent pointer to a node outside the tree is drawn as a dangling reference.
+					parent, ok := ids[child.parent]
+					if !ok {
+						parent = fmt.Sprintf("%q", reference(child.parent))
+					}
+					d.printf("\t%s -> %s [style=dashed, color=gray, constraint=false];\n", ids[child], parent)
+				}
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	d.printf("}\n")
+	return d.err
+}
+
+// LookupPath returns the nodes visited while searching for the value, from the root down to
+// the node holding it, or to the leaf where the search ended if the value is not in the tree.
+// It is meant for the Highlight option of WriteDOT.
+func LookupPath[T any](root *TwoThreeNode[T], value T) []*TwoThreeNode[T] {
+	var path []*TwoThreeNode[T]
+	node := root
+	for node != nil && node.firstData != nil {
+		path = append(path, node)
+
+		data := nodeData(node)
+		next := len(data)
+		for i, datum := range data {
+			c := node.comparator(value, *datum)
+			if c == 0 {
+				return path
+			}
+			if c < 0 {
+				next = i
+				break
+			}
+		}
+
+		children := nodeChildren(node)
+		if next >= len(children) {
+			break
+		}
+		node = children[next]
+	}
+	return path
+}
+
+// WriteDOT writes the tree as a Graphviz DOT digraph. See WriteDOT.
+func (tree *TwoThreeTree[T]) WriteDOT(w io.Writer, opts DOTOptions[T]) error {
+	return WriteDOT(w, tree.root, opts)
+}
@@ -0,0 +1,119 @@
+package trees
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	root := buildIntTree(t, 10, 20, 30)
+	tests := []struct {
+		name string
+		root *TwoThreeNode[int]
+		opts DOTOptions[int]
+		want string
+	}{
+		{
+			name: "It draws each node as a record with edges to its children",
+			root: root,
+			opts: DOTOptions[int]{},
+			want: `digraph "TwoThreeTree" {
+	node [shape=record];
+	n0 [label="<c0>|20|<c1>"];
+	n0:c0 -> n1;
+	n0:c1 -> n2;
+	n1 [label="10"];
+	n2 [label="30"];
+}
+`,
+		},
+		{
+			name: "It draws heights, parent edges and highlighted nodes",
+			root: root,
+			opts: DOTOptions[int]{Name: "incident", ParentEdges: true, Heights: true, Highlight: LookupPath(root, 30)},
+			want: `digraph "incident" {
+	node [shape=record];
+	n0 [label="{{<c0>|20|<c1>}|h: 2}", style=filled, fillcolor=gold];
+	n0:c0 -> n1;
+	n1 -> n0 [style=dashed, color=gray, constraint=false];
+	n0:c1 -> n2;
+	n2 -> n0 [style=dashed, color=gray, constraint=false];
+	n1 [label="{{10}|h: 1}"];
+	n2 [label="{{30}|h: 1}", style=filled, fillcolor=gold];
+}
+`,
+		},
+		{
+			name: "It draws an empty graph for an empty tree",
+			root: nil,
+			opts: DOTOptions[int]{},
+			want: "digraph \"TwoThreeTree\" {\n\tnode [shape=record];\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := WriteDOT(&sb, tt.root, tt.opts); err != nil {
+				t.Fatalf("WriteDOT() error = %v", err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteDOT() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteDOTEscapesLabels(t *testing.T) {
+	tree := NewTwoThreeTree(func(a, b string) int { return strings.Compare(a, b) })
+	if err := tree.Insert(`a|b<c>{"d"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+	if err := tree.WriteDOT(&sb, DOTOptions[string]{}); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+	if want := `label="a\|b\<c\>\{\"d\"\}"`; !strings.Contains(sb.String(), want) {
+		t.Errorf("WriteDOT() = %v, want it to contain %v", sb.String(), want)
+	}
+}
+
+type failingWriter struct{}
+
+var errWriteFailed = errors.New("write failed")
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWriteFailed
+}
+
+func TestWriteDOTReturnsWriteErrors(t *testing.T) {
+	if err := WriteDOT(failingWriter{}, buildThreeLevelTree(), DOTOptions[int]{}); !errors.Is(err, errWriteFailed) {
+		t.Errorf("WriteDOT() error = %v, want %v", err, errWriteFailed)
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	root := buildThreeLevelTree()
+	tests := []struct {
+		name  string
+		value int
+		want  []int
+	}{
+		{name: "It stops at the root when it holds the value", value: 25, want: []int{10}},
+		{name: "It descends to the node holding the value", value: 20, want: []int{10, 17, 20}},
+		{name: "It ends at a leaf when the value is missing", value: 36, want: []int{10, 40, 35}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, node := range LookupPath(root, tt.value) {
+				got = append(got, *node.firstData)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LookupPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
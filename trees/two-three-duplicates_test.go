@@ -0,0 +1,79 @@
+package trees
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// record is a value whose comparator only looks at the key, so records with
+// the same key but different ids are duplicates of each other.
+type record struct {
+	key, id int
+}
+
+func recordComparator(a, b record) int {
+	return intComparator(a.key, b.key)
+}
+
+func TestInsertRejectDuplicates(t *testing.T) {
+	tree := NewTwoThreeTreeWithPolicy(recordComparator, RejectDuplicates)
+	for i, key := range []int{5, 3, 8, 1} {
+		if err := tree.Insert(record{key, i}); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+
+	if err := tree.Insert(record{3, 10}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Insert() error = %v, want ErrDuplicate", err)
+	}
+	if got, _ := tree.Get(record{key: 3}); got.id != 1 {
+		t.Errorf("Get() = %v, want the original record", got)
+	}
+	if got := tree.Len(); got != 4 {
+		t.Errorf("Len() = %v, want 4", got)
+	}
+}
+
+func TestInsertReplaceDuplicates(t *testing.T) {
+	tree := NewTwoThreeTreeWithPolicy(recordComparator, ReplaceDuplicates)
+	for i, key := range []int{5, 3, 8, 1, 3, 5} {
+		if err := tree.Insert(record{key, i}); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+
+	want := []record{{1, 3}, {3, 4}, {5, 5}, {8, 2}}
+	if got := InOrder(tree.Root()); !reflect.DeepEqual(got, want) {
+		t.Errorf("InOrder() = %v, want %v", got, want)
+	}
+	if got := tree.Len(); got != 4 {
+		t.Errorf("Len() = %v, want 4", got)
+	}
+}
+
+func TestInsertAllowDuplicates(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	root := New(record{key: 5, id: 0}, recordComparator)
+
+	// Only a handful of keys, so separators are frequently equal to inserted values.
+	for id := 1; id < 500; id++ {
+		var err error
+		if root, err = Insert(root, record{r.Intn(10), id}); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+		checkShape(t, root)
+	}
+
+	got := InOrder(root)
+	if len(got) != 500 {
+		t.Fatalf("InOrder() returned %d records, want 500", len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		prev, cur := got[i-1], got[i]
+		if prev.key > cur.key || (prev.key == cur.key && prev.id > cur.id) {
+			t.Fatalf("InOrder() has %v before %v, want ascending keys in insertion order", prev, cur)
+		}
+	}
+}
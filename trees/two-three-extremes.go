@@ -0,0 +1,79 @@
+package trees
+
+// Min finds the smallest value of the tree.
+// It returns the value, and whether the tree has any values.
+func Min[T any](root *TwoThreeNode[T]) (T, bool) {
+	var zeroVal T
+	if root == nil || root.firstData == nil {
+		return zeroVal, false
+	}
+	return *leftmostLeaf(root).firstData, true
+}
+
+// Max finds the largest value of the tree.
+// It returns the value, and whether the tree has any values.
+func Max[T any](root *TwoThreeNode[T]) (T, bool) {
+	var zeroVal T
+	if root == nil || root.firstData == nil {
+		return zeroVal, false
+	}
+	data := nodeData(rightmostLeaf(root))
+	return *data[len(data)-1], true
+}
+
+// PopMin removes the smallest value from the tree.
+// Note that the root of the tree may be modified by this operation, and is nil once the last value is removed.
+// It returns the root node of the tree, the value removed, and whether the tree had any values.
+func PopMin[T any](root *TwoThreeNode[T]) (*TwoThreeNode[T], T, bool) {
+	value, ok := Min(root)
+	if !ok {
+		return root, value, false
+	}
+	return removeDatum(leftmostLeaf(root), 0), value, true
+}
+
+// PopMax removes the largest value from the tree.
+// Note that the root of the tree may be modified by this operation, and is nil once the last value is removed.
+// It returns the root node of the tree, the value removed, and whether the tree had any values.
+func PopMax[T any](root *TwoThreeNode[T]) (*TwoThreeNode[T], T, bool) {
+	value, ok := Max(root)
+	if !ok {
+		return root, value, false
+	}
+	leaf := rightmostLeaf(root)
+	return removeDatum(leaf, datumCount(leaf)-1), value, true
+}
+
+// Min finds the smallest value of the tree.
+// It returns the value, and whether the tree has any values.
+func (tree *TwoThreeTree[T]) Min() (T, bool) {
+	return Min(tree.root)
+}
+
+// Max finds the largest value of the tree.
+// It returns the value, and whether the tree has any values.
+func (tree *TwoThreeTree[T]) Max() (T, bool) {
+	return Max(tree.root)
+}
+
+// PopMin removes the smallest value from the tree.
+// It returns the value removed, and whether the tree had any values.
+func (tree *TwoThreeTree[T]) PopMin() (T, bool) {
+	root, value, ok := PopMin(tree.root)
+	tree.root = root
+	if ok {
+		tree.size--
+	}
+	return value, ok
+}
+
+// PopMax removes the largest value from the tree.
+// It returns the value removed, and whether the tree had any values.
+func (tree *TwoThreeTree[T]) PopMax() (T, bool) {
+	root, value, ok := PopMax(tree.root)
+	tree.root = root
+	if ok {
+		tree.size--
+	}
+	return value, ok
+}
@@ -0,0 +1,67 @@
+package trees
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMinMax(t *testing.T) {
+	root := buildThreeLevelTree()
+	if got, ok := Min(root); !ok || got != 5 {
+		t.Errorf("Min() = %v, %v, want 5, true", got, ok)
+	}
+	if got, ok := Max(root); !ok || got != 45 {
+		t.Errorf("Max() = %v, %v, want 45, true", got, ok)
+	}
+
+	tree := NewTwoThreeTree(intComparator)
+	if _, ok := tree.Min(); ok {
+		t.Errorf("Min() found a value in an empty tree")
+	}
+	if _, ok := tree.Max(); ok {
+		t.Errorf("Max() found a value in an empty tree")
+	}
+}
+
+func TestPopMin(t *testing.T) {
+	root := buildIntTree(t, rand.New(rand.NewSource(1)).Perm(100)...)
+
+	for want := 0; want < 100; want++ {
+		var got int
+		var ok bool
+		root, got, ok = PopMin(root)
+		if !ok || got != want {
+			t.Fatalf("PopMin() = %v, %v, want %v, true", got, ok, want)
+		}
+		if root != nil {
+			checkShape(t, root)
+		}
+	}
+
+	if _, _, ok := PopMin(root); ok || root != nil {
+		t.Errorf("PopMin() found a value in an empty tree")
+	}
+}
+
+func TestPopMax(t *testing.T) {
+	tree := NewTwoThreeTree(intComparator)
+	for _, value := range rand.New(rand.NewSource(1)).Perm(100) {
+		tree.Insert(value)
+	}
+
+	for want := 99; want >= 0; want-- {
+		if got, ok := tree.PopMax(); !ok || got != want {
+			t.Fatalf("PopMax() = %v, %v, want %v, true", got, ok, want)
+		}
+		if tree.Len() != want {
+			t.Fatalf("Len() = %v, want %v", tree.Len(), want)
+		}
+		if tree.Root() != nil {
+			checkShape(t, tree.Root())
+		}
+	}
+
+	if _, ok := tree.PopMax(); ok {
+		t.Errorf("PopMax() found a value in an empty tree")
+	}
+}
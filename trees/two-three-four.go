@@ -0,0 +1,155 @@
+package trees
+
+// TwoThreeFour is a 2-3-4 tree: a B-tree of order 4, whose nodes hold one, two or three values.
+// Unlike BTree, it inserts top-down, splitting every 4-node met on the way down to the leaf,
+// so a single pass from the root suffices. This is the insertion a left-leaning red-black tree
+// mirrors, so the two trees keep corresponding shapes; see ToRedBlack.
+// Lookups, deletion, traversal and validation are those of BTree, which all keep a valid 2-3-4 tree valid;
+// BTree's bottom-up Insert is not exposed.
+// The zero value is not usable; call NewTwoThreeFour.
+type TwoThreeFour[T any] struct {
+	tree BTree[T]
+}
+
+// NewTwoThreeFour is a constructor for an empty 2-3-4 tree ordered by the given comparator.
+// The tree allows duplicate values.
+func NewTwoThreeFour[T any](comparator func(a, b T) int) *TwoThreeFour[T] {
+	return NewTwoThreeFourWithPolicy(comparator, AllowDuplicates)
+}
+
+// NewTwoThreeFourWithPolicy is a constructor for an empty 2-3-4 tree ordered by the given comparator,
+// which handles duplicate values according to the given policy.
+func NewTwoThreeFourWithPolicy[T any](comparator func(a, b T) int, duplicates DuplicatePolicy) *TwoThreeFour[T] {
+	return &TwoThreeFour[T]{
+		tree: BTree[T]{
+			root:       nil,
+			comparator: comparator,
+			duplicates: duplicates,
+			order:      4,
+			size:       0,
+		},
+	}
+}
+
+// splitChild splits the 4-node at child i of the given node, moving its middle value up into node,
+// which must not itself be a 4-node.
+func splitChild[T any](node *bTreeNode[T], i int) {
+	child := node.children[i]
+	right := &bTreeNode[T]{keys: []T{child.keys[2]}}
+	if !child.isLeaf() {
+		right.children = []*bTreeNode[T]{child.children[2], child.children[3]}
+		child.children = child.children[:2:2]
+	}
+	node.keys = insertAt(node.keys, i, child.keys[1])
+	node.children = insertAt(node.children, i+1, right)
+	child.keys = child.keys[:1:1]
+}
+
+// Insert inserts a value into the tree, placing it after any values equal to it.
+// A value equal to one already in the tree is handled according to the tree's DuplicatePolicy.
+func (tree *TwoThreeFour[T]) Insert(value T) error {
+	if tree.tree.duplicates != AllowDuplicates {
+		if node, i := tree.tree.find(value); node != nil {
+			if tree.tree.duplicates == RejectDuplicates {
+				return ErrDuplicate
+			}
+			node.keys[i] = value
+			return nil
+		}
+	}
+
+	if tree.tree.root == nil {
+		tree.tree.root = &bTreeNode[T]{keys: []T{value}}
+		tree.tree.size++
+		return nil
+	}
+
+	// A full root is split first, which is the only way the tree grows taller.
+	if len(tree.tree.root.keys) == 3 {
+		tree.tree.root = &bTreeNode[T]{children: []*bTreeNode[T]{tree.tree.root}}
+		splitChild(tree.tree.root, 0)
+	}
+
+	node := tree.tree.root
+	for !node.isLeaf() {
+		i := tree.tree.upperBound(node, value)
+		if len(node.children[i].keys) == 3 {
+			splitChild(node, i)
+			if tree.tree.comparator(value, node.keys[i]) >= 0 {
+				i++
+			}
+		}
+		node = node.children[i]
+	}
+	node.keys = insertAt(node.keys, tree.tree.upperBound(node, value), value)
+	tree.tree.size++
+	return nil
+}
+
+// Order returns the most children a node of the tree may have, which is 4.
+func (tree *TwoThreeFour[T]) Order() int {
+	return tree.tree.Order()
+}
+
+// Len returns the number of values stored in the tree.
+func (tree *TwoThreeFour[T]) Len() int {
+	return tree.tree.Len()
+}
+
+// Height returns the number of levels in the tree, or 0 if the tree is empty.
+func (tree *TwoThreeFour[T]) Height() int {
+	return tree.tree.Height()
+}
+
+// Get finds a value in the tree.
+// It returns the stored value equal to the given one, and whether it was found.
+func (tree *TwoThreeFour[T]) Get(value T) (T, bool) {
+	return tree.tree.Get(value)
+}
+
+// Contains reports whether a value equal to the given one is stored in the tree.
+func (tree *TwoThreeFour[T]) Contains(value T) bool {
+	return tree.tree.Contains(value)
+}
+
+// Delete removes a value from the tree.
+// It returns whether the value was found.
+func (tree *TwoThreeFour[T]) Delete(value T) bool {
+	return tree.tree.Delete(value)
+}
+
+// Ascend calls fn for each value of the tree in ascending order.
+// Iteration stops early if fn returns false.
+func (tree *TwoThreeFour[T]) Ascend(fn func(T) bool) {
+	tree.tree.Ascend(fn)
+}
+
+// AscendRange calls fn, in ascending order, for each value of the tree in the half-open range [lo, hi).
+// Iteration stops early if fn returns false.
+func (tree *TwoThreeFour[T]) AscendRange(lo, hi T, fn func(T) bool) {
+	tree.tree.AscendRange(lo, hi, fn)
+}
+
+// Range finds the values of the tree between lo and hi.
+// The inclusivity determines whether values equal to lo or hi are part of the range.
+// It returns the values in ascending order.
+func (tree *TwoThreeFour[T]) Range(lo, hi T, inclusivity Inclusivity) []T {
+	return tree.tree.Range(lo, hi, inclusivity)
+}
+
+// InOrder returns the values of the tree in ascending order.
+func (tree *TwoThreeFour[T]) InOrder() []T {
+	return tree.tree.InOrder()
+}
+
+// BFS traverses the tree in breadth-first order.
+// It returns the keys of each node in the order visited.
+func (tree *TwoThreeFour[T]) BFS() []T {
+	return tree.tree.BFS()
+}
+
+// Validate checks every structural invariant of the tree, as well as its element count.
+// It returns nil if the tree is valid, or a *ValidationError listing every violation.
+func (tree *TwoThreeFour[T]) Validate() error {
+	return tree.tree.Validate()
+}
@@ -0,0 +1,99 @@
+package trees
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTwoThreeFourInsert(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []int
+		wantBFS []int
+	}{
+		{name: "It fills a single leaf up to three values", values: []int{20, 10, 30}, wantBFS: []int{10, 20, 30}},
+		{name: "It splits a full root before descending", values: []int{20, 10, 30, 40}, wantBFS: []int{20, 10, 30, 40}},
+		{name: "It splits full children on the way down", values: []int{20, 10, 30, 40, 50, 60}, wantBFS: []int{20, 40, 10, 30, 50, 60}},
+		{name: "It places duplicates after equal values", values: []int{5, 5, 5, 5}, wantBFS: []int{5, 5, 5, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTwoThreeFour(intComparator)
+			for _, value := range tt.values {
+				if err := tree.Insert(value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := tree.BFS(); !reflect.DeepEqual(got, tt.wantBFS) {
+				t.Errorf("BFS() = %v, want %v", got, tt.wantBFS)
+			}
+			if err := tree.Validate(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestTwoThreeFourRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	tree := NewTwoThreeFour(intComparator)
+	var want []int
+
+	for i := 0; i < 3000; i++ {
+		value := rng.Intn(500)
+		if rng.Intn(3) == 0 {
+			j := sort.SearchInts(want, value)
+			found := j < len(want) && want[j] == value
+			if found {
+				want = append(want[:j], want[j+1:]...)
+			}
+			if got := tree.Delete(value); got != found {
+				t.Fatalf("Delete(%d) = %v, want %v", value, got, found)
+			}
+		} else {
+			if err := tree.Insert(value); err != nil {
+				t.Fatal(err)
+			}
+			want = insertAt(want, sort.SearchInts(want, value+1), value)
+		}
+	}
+
+	if err := tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := tree.InOrder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("InOrder() = %v, want %v", got, want)
+	}
+	if tree.Order() != 4 {
+		t.Errorf("Order() = %v, want 4", tree.Order())
+	}
+}
+
+func TestTwoThreeFourPolicies(t *testing.T) {
+	reject := NewTwoThreeFourWithPolicy(recordComparator, RejectDuplicates)
+	replace := NewTwoThreeFourWithPolicy(recordComparator, ReplaceDuplicates)
+	for i := 0; i < 20; i++ {
+		if err := reject.Insert(record{i, 0}); err != nil {
+			t.Fatal(err)
+		}
+		if err := replace.Insert(record{i, 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := reject.Insert(record{7, 1}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Insert() error = %v, want %v", err, ErrDuplicate)
+	}
+	if err := replace.Insert(record{7, 1}); err != nil {
+		t.Errorf("Insert() error = %v", err)
+	}
+	if got, _ := replace.Get(record{key: 7}); got.id != 1 {
+		t.Errorf("Get() = %v, want the replacement", got)
+	}
+	if reject.Len() != 20 || replace.Len() != 20 {
+		t.Errorf("Len() = %v, %v, want 20", reject.Len(), replace.Len())
+	}
+}
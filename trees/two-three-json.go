@@ -0,0 +1,152 @@
+package trees
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSONForm selects how a TwoThreeTree is encoded by MarshalJSON.
+type JSONForm int
+
+const (
+	// FlatJSON encodes the tree as an array of its values in ascending order, e.g. [5,7,8,10].
+	// Decoding rebuilds a perfectly balanced tree, whatever shape the encoded tree had.
+	FlatJSON JSONForm = iota
+
+	// StructuralJSON encodes the tree as nested nodes, e.g. {"values":[10],"children":[{"values":[5]},{"values":[15]}]},
+	// so decoding restores the exact shape of the encoded tree.
+	StructuralJSON
+)
+
+// ErrNoComparator is returned when decoding into a tree that was not built with a comparator.
+// Comparators cannot be encoded, so the tree to decode into must come from NewTwoThreeTree.
+var ErrNoComparator = errors.New("tree has no comparator")
+
+// jsonNode is the structural JSON form of a node.
+type jsonNode[T any] struct {
+	Values   []T            `json:"values"`
+	Children []*jsonNode[T] `json:"children,omitempty"`
+}
+
+// toJSONNode returns the structural JSON form of the subtree.
+func toJSONNode[T any](node *TwoThreeNode[T]) *jsonNode[T] {
+	out := &jsonNode[T]{Values: []T{}}
+	for _, datum := range nodeData(node) {
+		out.Values = append(out.Values, *datum)
+	}
+	for _, child := range nodeChildren(node) {
+		out.Children = append(out.Children, toJSONNode(child))
+	}
+	return out
+}
+
+// fromJSONNode builds the subtree described by the structural JSON form.
+// Heights and sizes are calculated, but the shape is not checked; see Validate.
+func fromJSONNode[T any](in *jsonNode[T], comparator func(a, b T) int, duplicates DuplicatePolicy) (*TwoThreeNode[T], error) {
+	if len(in.Values) > 2 || len(in.Children) > 3 {
+		return nil, fmt.Errorf("node has %d value(s) and %d child(ren)", len(in.Values), len(in.Children))
+	}
+
+	node := &TwoThreeNode[T]{comparator: comparator, duplicates: duplicates}
+	data := make([]*T, len(in.Values))
+	for i := range in.Values {
+		data[i] = &in.Values[i]
+	}
+	setNodeData(node, data)
+
+	var children []*TwoThreeNode[T]
+	for _, c := range in.Children {
+		if c == nil {
+			return nil, errors.New("node has a null child")
+		}
+		child, err := fromJSONNode(c, comparator, duplicates)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	setNodeChildren(node, children)
+
+	node.height = maxHeight(children...) + 1
+	resize(node)
+	return node, nil
+}
+
+// SetJSONForm sets the form MarshalJSON encodes the tree in. Trees are encoded in FlatJSON form by default.
+// UnmarshalJSON accepts either form, whatever the setting.
+func (tree *TwoThreeTree[T]) SetJSONForm(form JSONForm) {
+	tree.jsonForm = form
+}
+
+// MarshalJSON encodes the tree in the form chosen by SetJSONForm.
+func (tree *TwoThreeTree[T]) MarshalJSON() ([]byte, error) {
+	if tree.jsonForm == StructuralJSON {
+		if tree.root == nil {
+			return json.Marshal(&jsonNode[T]{Values: []T{}})
+		}
+		return json.Marshal(toJSONNode(tree.root))
+	}
+
+	values := InOrder(tree.root)
+	if values == nil {
+		values = []T{}
+	}
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON replaces the contents of the tree with the encoded values, in either form.
+// The tree keeps its comparator and DuplicatePolicy, so it must be created before decoding:
+//
+//	tree := NewTwoThreeTree(cmp)
+//	err := json.Unmarshal(data, tree)
+//
+// It returns ErrNoComparator if the tree has no comparator, an error wrapping ErrNotSorted
+// if the values are out of order, an error wrapping ErrDuplicate if the tree does not allow
+// duplicates and the values hold some, or a *ValidationError if a structural tree is malformed.
+// The tree is left unchanged when an error is returned.
+func (tree *TwoThreeTree[T]) UnmarshalJSON(data []byte) error {
+	if tree.comparator == nil {
+		return ErrNoComparator
+	}
+
+	var root *TwoThreeNode[T]
+	var values []T
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var in jsonNode[T]
+		if err := json.Unmarshal(data, &in); err != nil {
+			return err
+		}
+		if len(in.Values) > 0 || len(in.Children) > 0 {
+			var err error
+			if root, err = fromJSONNode(&in, tree.comparator, tree.duplicates); err != nil {
+				return err
+			}
+			if err := Validate(root); err != nil {
+				return err
+			}
+			values = InOrder(root)
+		}
+	} else {
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		if err := checkSorted(values, tree.comparator); err != nil {
+			return err
+		}
+		root = buildSorted(values, tree.comparator, tree.duplicates)
+	}
+
+	if tree.duplicates != AllowDuplicates {
+		for i := 1; i < len(values); i++ {
+			if tree.comparator(values[i-1], values[i]) == 0 {
+				return fmt.Errorf("%w: %v", ErrDuplicate, values[i])
+			}
+		}
+	}
+
+	tree.root = root
+	tree.size = len(values)
+	return nil
+}
@@ -0,0 +1,209 @@
+package trees
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		form   JSONForm
+		want   string
+	}{
+		{
+			name:   "It encodes the values in order in the flat form",
+			values: []int{20, 10, 30, 40},
+			form:   FlatJSON,
+			want:   `[10,20,30,40]`,
+		},
+		{
+			name:   "It encodes the node shape in the structural form",
+			values: []int{20, 10, 30, 40},
+			form:   StructuralJSON,
+			want:   `{"values":[20],"children":[{"values":[10]},{"values":[30,40]}]}`,
+		},
+		{
+			name: "It encodes an empty tree in the flat form",
+			form: FlatJSON,
+			want: `[]`,
+		},
+		{
+			name: "It encodes an empty tree in the structural form",
+			form: StructuralJSON,
+			want: `{"values":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTwoThreeTree(intComparator)
+			for _, value := range tt.values {
+				if err := tree.Insert(value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tree.SetJSONForm(tt.form)
+
+			got, err := json.Marshal(tree)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		policy  DuplicatePolicy
+		want    []int
+		wantBFS []int
+		wantErr error
+	}{
+		{
+			name:    "It bulk loads the flat form into a balanced tree",
+			data:    `[1,2,3,4,5,6,7]`,
+			want:    []int{1, 2, 3, 4, 5, 6, 7},
+			wantBFS: []int{3, 6, 1, 2, 4, 5, 7},
+		},
+		{
+			name:    "It restores the shape of the structural form",
+			data:    `{"values":[20],"children":[{"values":[10]},{"values":[30,40]}]}`,
+			want:    []int{10, 20, 30, 40},
+			wantBFS: []int{20, 10, 30, 40},
+		},
+		{
+			name: "It decodes an empty flat tree",
+			data: `[]`,
+		},
+		{
+			name: "It decodes an empty structural tree",
+			data: ` {"values":[]}`,
+		},
+		{
+			name:    "It rejects flat values that are out of order",
+			data:    `[1,3,2]`,
+			wantErr: ErrNotSorted,
+		},
+		{
+			name:    "It rejects duplicates when the tree does not allow them",
+			data:    `[1,2,2]`,
+			policy:  RejectDuplicates,
+			wantErr: ErrDuplicate,
+		},
+		{
+			name:    "It keeps duplicates when the tree allows them",
+			data:    `[1,2,2]`,
+			want:    []int{1, 2, 2},
+			wantBFS: []int{2, 1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTwoThreeTreeWithPolicy(intComparator, tt.policy)
+			err := json.Unmarshal([]byte(tt.data), tree)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Unmarshal() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got := InOrder(tree.Root()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InOrder() = %v, want %v", got, tt.want)
+			}
+			if got := BFS(tree.Root()); !reflect.DeepEqual(got, tt.wantBFS) {
+				t.Errorf("BFS() = %v, want %v", got, tt.wantBFS)
+			}
+			if err := tree.Validate(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSONRejectsMalformedStructure(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "It rejects leaves at different depths", data: `{"values":[20],"children":[{"values":[10]},{"values":[30],"children":[{"values":[25]},{"values":[35]}]}]}`},
+		{name: "It rejects values on the wrong side of a separator", data: `{"values":[20],"children":[{"values":[25]},{"values":[30]}]}`},
+		{name: "It rejects a node with too many values", data: `{"values":[1,2,3]}`},
+		{name: "It rejects a node with a missing child", data: `{"values":[10,20],"children":[{"values":[5]},{"values":[15]}]}`},
+		{name: "It rejects a null child", data: `{"values":[10],"children":[{"values":[5]},null]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTwoThreeTree(intComparator)
+			if err := tree.Insert(1); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.data), tree); err == nil {
+				t.Fatalf("Unmarshal() accepted %s", tt.data)
+			}
+			if got := InOrder(tree.Root()); !reflect.DeepEqual(got, []int{1}) || tree.Len() != 1 {
+				t.Errorf("Unmarshal() changed the tree to %v after failing", got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSONRequiresComparator(t *testing.T) {
+	var tree TwoThreeTree[int]
+	if err := json.Unmarshal([]byte(`[1]`), &tree); !errors.Is(err, ErrNoComparator) {
+		t.Errorf("Unmarshal() error = %v, want %v", err, ErrNoComparator)
+	}
+}
+
+type event struct {
+	At int    `json:"at"`
+	ID string `json:"id"`
+}
+
+func eventComparator(a, b event) int {
+	return intComparator(a.At, b.At)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	type payload struct {
+		Tree *TwoThreeTree[event] `json:"tree"`
+	}
+
+	for _, form := range []JSONForm{FlatJSON, StructuralJSON} {
+		tree := NewTwoThreeTree(eventComparator)
+		for i := 0; i < 50; i++ {
+			if err := tree.Insert(event{At: (i * 7) % 20, ID: fmt.Sprint(i)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		tree.SetJSONForm(form)
+
+		data, err := json.Marshal(payload{Tree: tree})
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		decoded := payload{Tree: NewTwoThreeTree(eventComparator)}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		if got, want := InOrder(decoded.Tree.Root()), InOrder(tree.Root()); !reflect.DeepEqual(got, want) {
+			t.Errorf("form %d: decoded %v, want %v", form, got, want)
+		}
+		if form == StructuralJSON && !reflect.DeepEqual(BFS(decoded.Tree.Root()), BFS(tree.Root())) {
+			t.Errorf("structural form did not keep the shape of the tree")
+		}
+		if err := decoded.Tree.Validate(); err != nil {
+			t.Error(err)
+		}
+	}
+}
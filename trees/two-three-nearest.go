@@ -0,0 +1,104 @@
+package trees
+
+// floor descends from node towards value, tracking the greatest value below it.
+// When strict is false, values equal to the given one also qualify.
+// It returns the value found, and whether one exists.
+func floor[T any](node *TwoThreeNode[T], value T, strict bool) (T, bool) {
+	var best T
+	found := false
+
+	for node != nil && node.firstData != nil {
+		children := nodeChildren(node)
+		next := 0
+		for i, datum := range nodeData(node) {
+			c := node.comparator(*datum, value)
+			if c > 0 || (c == 0 && strict) {
+				break
+			}
+			best, found = *datum, true
+			next = i + 1
+		}
+		if len(children) == 0 {
+			break
+		}
+		node = children[next]
+	}
+
+	return best, found
+}
+
+// ceiling descends from node towards value, tracking the smallest value above it.
+// When strict is false, values equal to the given one also qualify.
+// It returns the value found, and whether one exists.
+func ceiling[T any](node *TwoThreeNode[T], value T, strict bool) (T, bool) {
+	var best T
+	found := false
+
+	for node != nil && node.firstData != nil {
+		children := nodeChildren(node)
+		data := nodeData(node)
+		next := len(data)
+		for i, datum := range data {
+			c := node.comparator(*datum, value)
+			if c > 0 || (c == 0 && !strict) {
+				best, found = *datum, true
+				next = i
+				break
+			}
+		}
+		if len(children) == 0 {
+			break
+		}
+		node = children[next]
+	}
+
+	return best, found
+}
+
+// Floor finds the greatest value of the tree that is less than or equal to the given value.
+// It returns the value, and whether one exists.
+func Floor[T any](root *TwoThreeNode[T], value T) (T, bool) {
+	return floor(root, value, false)
+}
+
+// Ceiling finds the smallest value of the tree that is greater than or equal to the given value.
+// It returns the value, and whether one exists.
+func Ceiling[T any](root *TwoThreeNode[T], value T) (T, bool) {
+	return ceiling(root, value, false)
+}
+
+// Predecessor finds the greatest value of the tree that is strictly less than the given value.
+// It returns the value, and whether one exists.
+func Predecessor[T any](root *TwoThreeNode[T], value T) (T, bool) {
+	return floor(root, value, true)
+}
+
+// Successor finds the smallest value of the tree that is strictly greater than the given value.
+// It returns the value, and whether one exists.
+func Successor[T any](root *TwoThreeNode[T], value T) (T, bool) {
+	return ceiling(root, value, true)
+}
+
+// Floor finds the greatest value of the tree that is less than or equal to the given value.
+// It returns the value, and whether one exists.
+func (tree *TwoThreeTree[T]) Floor(value T) (T, bool) {
+	return Floor(tree.root, value)
+}
+
+// Ceiling finds the smallest value of the tree that is greater than or equal to the given value.
+// It returns the value, and whether one exists.
+func (tree *TwoThreeTree[T]) Ceiling(value T) (T, bool) {
+	return Ceiling(tree.root, value)
+}
+
+// Predecessor finds the greatest value of the tree that is strictly less than the given value.
+// It returns the value, and whether one exists.
+func (tree *TwoThreeTree[T]) Predecessor(value T) (T, bool) {
+	return Predecessor(tree.root, value)
+}
+
+// Successor finds the smallest value of the tree that is strictly greater than the given value.
+// It returns the value, and whether one exists.
+func (tree *TwoThreeTree[T]) Successor(value T) (T, bool) {
+	return Successor(tree.root, value)
+}
@@ -0,0 +1,57 @@
+package trees
+
+import "testing"
+
+func TestNearest(t *testing.T) {
+	/*
+			            (10, 25)
+			  ----------------------------
+		     /           |               \
+			(7)         (17)             (40)
+			/  \        /  \            /  \
+		(5)    (8)    (15)    (20)    (35)    (45)
+	*/
+	type lookup func(*TwoThreeNode[int], int) (int, bool)
+	tests := []struct {
+		name      string
+		fn        lookup
+		value     int
+		want      int
+		wantFound bool
+	}{
+		{name: "Floor returns an exact match", fn: Floor[int], value: 25, want: 25, wantFound: true},
+		{name: "Floor returns the next smaller value", fn: Floor[int], value: 34, want: 25, wantFound: true},
+		{name: "Floor finds nothing below the smallest value", fn: Floor[int], value: 4, wantFound: false},
+		{name: "Ceiling returns an exact match", fn: Ceiling[int], value: 8, want: 8, wantFound: true},
+		{name: "Ceiling returns the next larger value", fn: Ceiling[int], value: 11, want: 15, wantFound: true},
+		{name: "Ceiling finds nothing above the largest value", fn: Ceiling[int], value: 46, wantFound: false},
+		{name: "Predecessor skips an exact match", fn: Predecessor[int], value: 10, want: 8, wantFound: true},
+		{name: "Predecessor finds nothing before the smallest value", fn: Predecessor[int], value: 5, wantFound: false},
+		{name: "Successor skips an exact match", fn: Successor[int], value: 20, want: 25, wantFound: true},
+		{name: "Successor finds nothing after the largest value", fn: Successor[int], value: 45, wantFound: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := tt.fn(buildThreeLevelTree(), tt.value)
+			if found != tt.wantFound || (found && got != tt.want) {
+				t.Errorf("got %v, %v, want %v, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestNearestEmptyTree(t *testing.T) {
+	tree := NewTwoThreeTree(intComparator)
+	if _, found := tree.Floor(1); found {
+		t.Errorf("Floor() found a value in an empty tree")
+	}
+	if _, found := tree.Ceiling(1); found {
+		t.Errorf("Ceiling() found a value in an empty tree")
+	}
+	if _, found := tree.Predecessor(1); found {
+		t.Errorf("Predecessor() found a value in an empty tree")
+	}
+	if _, found := tree.Successor(1); found {
+		t.Errorf("Successor() found a value in an empty tree")
+	}
+}
@@ -0,0 +1,95 @@
+package trees
+
+// Inclusivity specifies which bounds of a range are included in it.
+type Inclusivity int
+
+const (
+	// IncludeLow selects values equal to the lower bound.
+	IncludeLow Inclusivity = 1 << iota
+	// IncludeHigh selects values equal to the upper bound.
+	IncludeHigh
+	// ExcludeBoth selects values strictly between the bounds.
+	ExcludeBoth Inclusivity = 0
+	// IncludeBoth selects values equal to either bound.
+	IncludeBoth = IncludeLow | IncludeHigh
+)
+
+// aboveLow reports whether value is within the lower bound of a range.
+func aboveLow[T any](comparator func(T, T) int, value, lo T, inclusivity Inclusivity) bool {
+	c := comparator(value, lo)
+	return c > 0 || (c == 0 && inclusivity&IncludeLow != 0)
+}
+
+// belowHigh reports whether value is within the upper bound of a range.
+func belowHigh[T any](comparator func(T, T) int, value, hi T, inclusivity Inclusivity) bool {
+	c := comparator(value, hi)
+	return c < 0 || (c == 0 && inclusivity&IncludeHigh != 0)
+}
+
+// ascendRange calls fn, in ascending order, for each value of the subtree that lies within the range.
+// Children are only descended into when the separators around them overlap the range.
+// It returns false once fn returns false or a value above the range is reached.
+func ascendRange[T any](node *TwoThreeNode[T], lo, hi T, inclusivity Inclusivity, fn func(T) bool) bool {
+	children := nodeChildren(node)
+	data := nodeData(node)
+
+	for i := 0; i <= len(data); i++ {
+		// child i only holds values between data[i-1] and data[i].
+		if i < len(children) {
+			overlapsLow := i == len(data) || node.comparator(*data[i], lo) >= 0
+			overlapsHigh := i == 0 || node.comparator(*data[i-1], hi) <= 0
+			if overlapsLow && overlapsHigh && !ascendRange(children[i], lo, hi, inclusivity, fn) {
+				return false
+			}
+		}
+
+		if i == len(data) {
+			break
+		}
+		if !belowHigh(node.comparator, *data[i], hi, inclusivity) {
+			return false
+		}
+		if aboveLow(node.comparator, *data[i], lo, inclusivity) && !fn(*data[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AscendRange calls fn, in ascending order, for each value of the tree in the half-open range [lo, hi).
+// Iteration stops early if fn returns false.
+func AscendRange[T any](root *TwoThreeNode[T], lo, hi T, fn func(T) bool) {
+	if root == nil {
+		return
+	}
+	ascendRange(root, lo, hi, IncludeLow, fn)
+}
+
+// Range finds the values of the tree between lo and hi.
+// The inclusivity determines whether values equal to lo or hi are part of the range.
+// It returns the values in ascending order.
+func Range[T any](root *TwoThreeNode[T], lo, hi T, inclusivity Inclusivity) []T {
+	var result []T
+	if root == nil {
+		return result
+	}
+	ascendRange(root, lo, hi, inclusivity, func(value T) bool {
+		result = append(result, value)
+		return true
+	})
+	return result
+}
+
+// AscendRange calls fn, in ascending order, for each value of the tree in the half-open range [lo, hi).
+// Iteration stops early if fn returns false.
+func (tree *TwoThreeTree[T]) AscendRange(lo, hi T, fn func(T) bool) {
+	AscendRange(tree.root, lo, hi, fn)
+}
+
+// Range finds the values of the tree between lo and hi.
+// The inclusivity determines whether values equal to lo or hi are part of the range.
+// It returns the values in ascending order.
+func (tree *TwoThreeTree[T]) Range(lo, hi T, inclusivity Inclusivity) []T {
+	return Range(tree.root, lo, hi, inclusivity)
+}
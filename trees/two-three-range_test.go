@@ -0,0 +1,105 @@
+package trees
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+	type args struct {
+		lo, hi      int
+		inclusivity Inclusivity
+	}
+	tests := []struct {
+		name string
+		args args
+		want []int
+	}{
+		{
+			name: "It includes both bounds",
+			args: args{lo: 8, hi: 25, inclusivity: IncludeBoth},
+			want: []int{8, 10, 15, 17, 20, 25},
+		},
+		{
+			name: "It excludes both bounds",
+			args: args{lo: 8, hi: 25, inclusivity: ExcludeBoth},
+			want: []int{10, 15, 17, 20},
+		},
+		{
+			name: "It includes only the lower bound",
+			args: args{lo: 8, hi: 25, inclusivity: IncludeLow},
+			want: []int{8, 10, 15, 17, 20},
+		},
+		{
+			name: "It includes only the upper bound",
+			args: args{lo: 8, hi: 25, inclusivity: IncludeHigh},
+			want: []int{10, 15, 17, 20, 25},
+		},
+		{
+			name: "It handles bounds that are not in the tree",
+			args: args{lo: 0, hi: 100, inclusivity: ExcludeBoth},
+			want: []int{5, 7, 8, 10, 15, 17, 20, 25, 35, 40, 45},
+		},
+		{
+			name: "It returns nothing for an empty range",
+			args: args{lo: 21, hi: 24, inclusivity: IncludeBoth},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Range(buildThreeLevelTree(), tt.args.lo, tt.args.hi, tt.args.inclusivity)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Range() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAscendRange(t *testing.T) {
+	t.Run("It visits the half-open range in order", func(t *testing.T) {
+		var got []int
+		AscendRange(buildThreeLevelTree(), 10, 35, func(value int) bool {
+			got = append(got, value)
+			return true
+		})
+		if want := []int{10, 15, 17, 20, 25}; !reflect.DeepEqual(got, want) {
+			t.Errorf("AscendRange() visited %v, want %v", got, want)
+		}
+	})
+
+	t.Run("It stops when fn returns false", func(t *testing.T) {
+		var got []int
+		AscendRange(buildThreeLevelTree(), 0, 100, func(value int) bool {
+			got = append(got, value)
+			return len(got) < 3
+		})
+		if want := []int{5, 7, 8}; !reflect.DeepEqual(got, want) {
+			t.Errorf("AscendRange() visited %v, want %v", got, want)
+		}
+	})
+
+	t.Run("It only descends into subtrees that overlap the range", func(t *testing.T) {
+		comparisons := 0
+		tree := NewTwoThreeTree(func(a, b int) int {
+			comparisons++
+			return intComparator(a, b)
+		})
+		for i := 0; i < 1000; i++ {
+			tree.Insert(i)
+		}
+
+		comparisons = 0
+		var got []int
+		tree.AscendRange(500, 502, func(value int) bool {
+			got = append(got, value)
+			return true
+		})
+		if want := []int{500, 501}; !reflect.DeepEqual(got, want) {
+			t.Errorf("AscendRange() visited %v, want %v", got, want)
+		}
+		if limit := 8 * tree.Height(); comparisons > limit {
+			t.Errorf("AscendRange() made %d comparisons, want at most %d", comparisons, limit)
+		}
+	})
+}
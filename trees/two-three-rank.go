@@ -0,0 +1,109 @@
+package trees
+
+// childSize returns the number of values in the subtree, or 0 for a nil subtree.
+func childSize[T any](node *TwoThreeNode[T]) int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
+
+// countBelow counts the values of the tree that are less than the given value,
+// or less than or equal to it when inclusive is true.
+// It descends a single path, adding the sizes of the subtrees that lie entirely below the value.
+func countBelow[T any](node *TwoThreeNode[T], value T, inclusive bool) int {
+	count := 0
+
+	for node != nil && node.firstData != nil {
+		children := nodeChildren(node)
+		data := nodeData(node)
+		next := len(data)
+		for i, datum := range data {
+			c := node.comparator(*datum, value)
+			if c > 0 || (c == 0 && !inclusive) {
+				next = i
+				break
+			}
+			if i < len(children) {
+				count += children[i].size
+			}
+			count++
+		}
+		if len(children) == 0 {
+			break
+		}
+		node = children[next]
+	}
+
+	return count
+}
+
+// Select finds the k-th smallest value of the tree, counting from 0.
+// It returns the value, and whether k is within the bounds of the tree.
+func Select[T any](root *TwoThreeNode[T], k int) (T, bool) {
+	var zeroVal T
+	if root == nil || k < 0 || k >= root.size {
+		return zeroVal, false
+	}
+
+	node := root
+	for {
+		children := nodeChildren(node)
+		data := nodeData(node)
+		next := len(data)
+		for i, datum := range data {
+			var left *TwoThreeNode[T]
+			if i < len(children) {
+				left = children[i]
+			}
+			if k < childSize(left) {
+				next = i
+				break
+			}
+			if k == childSize(left) {
+				return *datum, true
+			}
+			k -= childSize(left) + 1
+		}
+		if len(children) == 0 {
+			return zeroVal, false
+		}
+		node = children[next]
+	}
+}
+
+// Rank counts the values of the tree that are strictly less than the given value.
+// When the value is in the tree, this is the index Select returns it at.
+func Rank[T any](root *TwoThreeNode[T], value T) int {
+	return countBelow(root, value, false)
+}
+
+// CountRange counts the values of the tree between lo and hi.
+// The inclusivity determines whether values equal to lo or hi are counted.
+func CountRange[T any](root *TwoThreeNode[T], lo, hi T, inclusivity Inclusivity) int {
+	if root == nil || root.firstData == nil || root.comparator(lo, hi) > 0 {
+		return 0
+	}
+	count := countBelow(root, hi, inclusivity&IncludeHigh != 0) - countBelow(root, lo, inclusivity&IncludeLow == 0)
+	if count < 0 {
+		return 0
+	}
+	return count
+}
+
+// Select finds the k-th smallest value of the tree, counting from 0.
+// It returns the value, and whether k is within the bounds of the tree.
+func (tree *TwoThreeTree[T]) Select(k int) (T, bool) {
+	return Select(tree.root, k)
+}
+
+// Rank counts the values of the tree that are strictly less than the given value.
+func (tree *TwoThreeTree[T]) Rank(value T) int {
+	return Rank(tree.root, value)
+}
+
+// CountRange counts the values of the tree between lo and hi.
+// The inclusivity determines whether values equal to lo or hi are counted.
+func (tree *TwoThreeTree[T]) CountRange(lo, hi T, inclusivity Inclusivity) int {
+	return CountRange(tree.root, lo, hi, inclusivity)
+}
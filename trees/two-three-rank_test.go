@@ -0,0 +1,79 @@
+package trees
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSelectAndRank(t *testing.T) {
+	root := buildIntTree(t, rand.New(rand.NewSource(1)).Perm(200)...)
+	for i := 0; i < 200; i += 2 {
+		root, _, _ = Delete(root, i)
+	}
+	// root now holds the odd numbers 1, 3, ..., 199.
+
+	for k := 0; k < 100; k++ {
+		if got, ok := Select(root, k); !ok || got != 2*k+1 {
+			t.Fatalf("Select(%d) = %v, %v, want %v, true", k, got, ok, 2*k+1)
+		}
+		if got := Rank(root, 2*k+1); got != k {
+			t.Fatalf("Rank(%d) = %v, want %v", 2*k+1, got, k)
+		}
+		if got := Rank(root, 2*k+2); got != k+1 {
+			t.Fatalf("Rank(%d) = %v, want %v", 2*k+2, got, k+1)
+		}
+	}
+
+	for _, k := range []int{-1, 100} {
+		if _, ok := Select(root, k); ok {
+			t.Errorf("Select(%d) found a value out of bounds", k)
+		}
+	}
+}
+
+func TestCountRange(t *testing.T) {
+	tree := NewTwoThreeTree(intComparator)
+	for _, value := range []int{10, 20, 5, 7, 25, 35, 40, 45, 8, 15, 17} {
+		tree.Insert(value)
+	}
+
+	tests := []struct {
+		name        string
+		lo, hi      int
+		inclusivity Inclusivity
+	}{
+		{name: "It counts both bounds", lo: 8, hi: 25, inclusivity: IncludeBoth},
+		{name: "It excludes both bounds", lo: 8, hi: 25, inclusivity: ExcludeBoth},
+		{name: "It counts only the lower bound", lo: 8, hi: 25, inclusivity: IncludeLow},
+		{name: "It counts only the upper bound", lo: 8, hi: 25, inclusivity: IncludeHigh},
+		{name: "It counts bounds outside the tree", lo: 0, hi: 100, inclusivity: IncludeBoth},
+		{name: "It counts an empty range", lo: 21, hi: 24, inclusivity: IncludeBoth},
+		{name: "It counts a single value", lo: 17, hi: 17, inclusivity: IncludeBoth},
+		{name: "It counts an inverted range as empty", lo: 25, hi: 8, inclusivity: IncludeBoth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := len(tree.Range(tt.lo, tt.hi, tt.inclusivity))
+			if got := tree.CountRange(tt.lo, tt.hi, tt.inclusivity); got != want {
+				t.Errorf("CountRange() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRankWithDuplicates(t *testing.T) {
+	tree := NewTwoThreeTree(intComparator)
+	for _, value := range []int{3, 1, 3, 2, 3, 4, 3} {
+		tree.Insert(value)
+	}
+
+	if got := tree.Rank(3); got != 2 {
+		t.Errorf("Rank(3) = %v, want 2", got)
+	}
+	if got := tree.CountRange(3, 3, IncludeBoth); got != 4 {
+		t.Errorf("CountRange(3, 3) = %v, want 4", got)
+	}
+	if got, _ := tree.Select(5); got != 3 {
+		t.Errorf("Select(5) = %v, want 3", got)
+	}
+}
@@ -0,0 +1,151 @@
+package trees
+
+// merge walks the values of both trees together in ascending order, calling fn once for each value
+// with whether it was found in a, in b, or in both. Equal values are paired off one to one,
+// so duplicates are treated as multiset elements.
+// Iteration stops early if fn returns false.
+func merge[T any](a, b *TwoThreeNode[T], fn func(value T, inA, inB bool) bool) {
+	cursorA, cursorB := NewCursor(a), NewCursor(b)
+	okA, okB := cursorA.First(), cursorB.First()
+
+	for okA || okB {
+		switch {
+		case !okB:
+			if !fn(cursorA.Value(), true, false) {
+				return
+			}
+			okA = cursorA.Next()
+		case !okA:
+			if !fn(cursorB.Value(), false, true) {
+				return
+			}
+			okB = cursorB.Next()
+		default:
+			valueA, valueB := cursorA.Value(), cursorB.Value()
+			switch c := a.comparator(valueA, valueB); {
+			case c < 0:
+				if !fn(valueA, true, false) {
+					return
+				}
+				okA = cursorA.Next()
+			case c > 0:
+				if !fn(valueB, false, true) {
+					return
+				}
+				okB = cursorB.Next()
+			default:
+				if !fn(valueA, true, true) {
+					return
+				}
+				okA, okB = cursorA.Next(), cursorB.Next()
+			}
+		}
+	}
+}
+
+// combine merges both trees, keeping the values for which keep returns true, and bulk loads them into a new tree.
+// It returns the root node of the new tree, which is nil when no values are kept.
+func combine[T any](a, b *TwoThreeNode[T], keep func(inA, inB bool) bool) *TwoThreeNode[T] {
+	like := a
+	if like == nil || like.firstData == nil {
+		like = b
+	}
+	if like == nil || like.firstData == nil {
+		return nil
+	}
+
+	var values []T
+	merge(a, b, func(value T, inA, inB bool) bool {
+		if keep(inA, inB) {
+			values = append(values, value)
+		}
+		return true
+	})
+	return buildSorted(values, like.comparator, like.duplicates)
+}
+
+// Union builds a new tree holding the values found in either tree.
+// Both trees must use the same comparator. It runs in time linear in the size of both trees.
+// It returns the root node of the new tree, which is nil when it holds no values.
+func Union[T any](a, b *TwoThreeNode[T]) *TwoThreeNode[T] {
+	return combine(a, b, func(inA, inB bool) bool { return true })
+}
+
+// Intersection builds a new tree holding the values found in both trees.
+// Both trees must use the same comparator. It runs in time linear in the size of both trees.
+// It returns the root node of the new tree, which is nil when it holds no values.
+func Intersection[T any](a, b *TwoThreeNode[T]) *TwoThreeNode[T] {
+	return combine(a, b, func(inA, inB bool) bool { return inA && inB })
+}
+
+// Difference builds a new tree holding the values of a that are not found in b.
+// Both trees must use the same comparator. It runs in time linear in the size of both trees.
+// It returns the root node of the new tree, which is nil when it holds no values.
+func Difference[T any](a, b *TwoThreeNode[T]) *TwoThreeNode[T] {
+	return combine(a, b, func(inA, inB bool) bool { return inA && !inB })
+}
+
+// SymmetricDifference builds a new tree holding the values found in exactly one of the trees.
+// Both trees must use the same comparator. It runs in time linear in the size of both trees.
+// It returns the root node of the new tree, which is nil when it holds no values.
+func SymmetricDifference[T any](a, b *TwoThreeNode[T]) *TwoThreeNode[T] {
+	return combine(a, b, func(inA, inB bool) bool { return inA != inB })
+}
+
+// IsSubset reports whether every value of a is also found in b.
+func IsSubset[T any](a, b *TwoThreeNode[T]) bool {
+	subset := true
+	merge(a, b, func(value T, inA, inB bool) bool {
+		subset = !inA || inB
+		return subset
+	})
+	return subset
+}
+
+// Disjoint reports whether the trees have no values in common.
+func Disjoint[T any](a, b *TwoThreeNode[T]) bool {
+	disjoint := true
+	merge(a, b, func(value T, inA, inB bool) bool {
+		disjoint = !(inA && inB)
+		return disjoint
+	})
+	return disjoint
+}
+
+// fromRoot wraps a root built from this tree's values in a new tree with the same comparator and duplicate policy.
+func (tree *TwoThreeTree[T]) fromRoot(root *TwoThreeNode[T]) *TwoThreeTree[T] {
+	result := NewTwoThreeTreeWithPolicy(tree.comparator, tree.duplicates)
+	result.root = root
+	result.size = childSize(root)
+	return result
+}
+
+// Union builds a new tree holding the values found in either tree.
+func (tree *TwoThreeTree[T]) Union(other *TwoThreeTree[T]) *TwoThreeTree[T] {
+	return tree.fromRoot(Union(tree.root, other.root))
+}
+
+// Intersection builds a new tree holding the values found in both trees.
+func (tree *TwoThreeTree[T]) Intersection(other *TwoThreeTree[T]) *TwoThreeTree[T] {
+	return tree.fromRoot(Intersection(tree.root, other.root))
+}
+
+// Difference builds a new tree holding the values of the tree that are not found in other.
+func (tree *TwoThreeTree[T]) Difference(other *TwoThreeTree[T]) *TwoThreeTree[T] {
+	return tree.fromRoot(Difference(tree.root, other.root))
+}
+
+// SymmetricDifference builds a new tree holding the values found in exactly one of the trees.
+func (tree *TwoThreeTree[T]) SymmetricDifference(other *TwoThreeTree[T]) *TwoThreeTree[T] {
+	return tree.fromRoot(SymmetricDifference(tree.root, other.root))
+}
+
+// IsSubset reports whether every value of the tree is also found in other.
+func (tree *TwoThreeTree[T]) IsSubset(other *TwoThreeTree[T]) bool {
+	return IsSubset(tree.root, other.root)
+}
+
+// Disjoint reports whether the trees have no values in common.
+func (tree *TwoThreeTree[T]) Disjoint(other *TwoThreeTree[T]) bool {
+	return Disjoint(tree.root, other.root)
+}
@@ -0,0 +1,106 @@
+package trees
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetAlgebra(t *testing.T) {
+	a := buildIntTree(t, 1, 3, 5, 7, 9, 11)
+	b := buildIntTree(t, 3, 4, 5, 6, 7)
+
+	tests := []struct {
+		name string
+		fn   func(a, b *TwoThreeNode[int]) *TwoThreeNode[int]
+		want []int
+	}{
+		{name: "Union", fn: Union[int], want: []int{1, 3, 4, 5, 6, 7, 9, 11}},
+		{name: "Intersection", fn: Intersection[int], want: []int{3, 5, 7}},
+		{name: "Difference", fn: Difference[int], want: []int{1, 9, 11}},
+		{name: "SymmetricDifference", fn: SymmetricDifference[int], want: []int{1, 4, 6, 9, 11}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.fn(a, b)
+			checkShape(t, got)
+			if values := InOrder(got); !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("%s() = %v, want %v", tt.name, values, tt.want)
+			}
+		})
+	}
+
+	// The inputs are left untouched.
+	if got := InOrder(a); !reflect.DeepEqual(got, []int{1, 3, 5, 7, 9, 11}) {
+		t.Errorf("a changed to %v", got)
+	}
+}
+
+func TestSetAlgebraEmpty(t *testing.T) {
+	a := buildIntTree(t, 1, 2)
+
+	if got := InOrder(Union(nil, a)); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Union() = %v, want [1 2]", got)
+	}
+	if got := Intersection(a, nil); got != nil {
+		t.Errorf("Intersection() = %v, want nil", InOrder(got))
+	}
+	if got := Difference[int](nil, nil); got != nil {
+		t.Errorf("Difference() = %v, want nil", InOrder(got))
+	}
+}
+
+func TestSubsetAndDisjoint(t *testing.T) {
+	small := buildIntTree(t, 2, 4)
+	large := buildIntTree(t, 1, 2, 3, 4, 5)
+	other := buildIntTree(t, 6, 7)
+
+	tests := []struct {
+		name     string
+		got      bool
+		want     bool
+		function string
+	}{
+		{function: "IsSubset", name: "small of large", got: IsSubset(small, large), want: true},
+		{function: "IsSubset", name: "large of small", got: IsSubset(large, small), want: false},
+		{function: "IsSubset", name: "empty of small", got: IsSubset(nil, small), want: true},
+		{function: "Disjoint", name: "large and other", got: Disjoint(large, other), want: true},
+		{function: "Disjoint", name: "small and large", got: Disjoint(small, large), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function+" "+tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("%s() = %v, want %v", tt.function, tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTwoThreeTreeSetAlgebra(t *testing.T) {
+	a := NewTwoThreeTreeWithPolicy(intComparator, RejectDuplicates)
+	b := NewTwoThreeTreeWithPolicy(intComparator, RejectDuplicates)
+	for i := 0; i < 100; i++ {
+		a.Insert(i)
+		b.Insert(i + 50)
+	}
+
+	union := a.Union(b)
+	if err := union.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if union.Len() != 150 {
+		t.Errorf("Union().Len() = %v, want 150", union.Len())
+	}
+	if err := union.Insert(10); err != ErrDuplicate {
+		t.Errorf("Union().Insert() error = %v, want ErrDuplicate", err)
+	}
+
+	if got := a.Intersection(b).Len(); got != 50 {
+		t.Errorf("Intersection().Len() = %v, want 50", got)
+	}
+	if !a.Intersection(b).IsSubset(a) || a.Difference(b).Disjoint(a) || !a.Difference(b).Disjoint(b) {
+		t.Errorf("set predicates disagree with Intersection and Difference")
+	}
+	if got := a.SymmetricDifference(b).Len(); got != 100 {
+		t.Errorf("SymmetricDifference().Len() = %v, want 100", got)
+	}
+}
@@ -0,0 +1,506 @@
+package trees
+
+import (
+	"fmt"
+	"sort"
+)
+
+// slabNil is the index of no node.
+const slabNil int32 = -1
+
+// slabNoChildren is the children of a node without any.
+var slabNoChildren = [3]int32{slabNil, slabNil, slabNil}
+
+// slabNode is a node of a SlabTree. Its values are held inline and its children by their index in the slab,
+// so a node holds no pointers of its own.
+type slabNode[T any] struct {
+	// values holds the node's count values in ascending order.
+	values [2]T
+
+	// children holds the indexes of an internal node's count+1 children. Every other slot is slabNil.
+	children [3]int32
+
+	count int8
+	leaf  bool
+}
+
+// keys returns the node's values.
+func (node *slabNode[T]) keys() []T {
+	return node.values[:node.count]
+}
+
+// SlabTree is a two-three tree laid out for the garbage collector.
+// A TwoThreeTree allocates every node separately and holds every value through a pointer, so a large tree is
+// many millions of objects for the collector to scan. A SlabTree instead stores its nodes in a single slice,
+// addressed by index, with values inline: growing the tree only allocates when the slice does, and when T holds
+// no pointers the collector does not scan the tree at all. Nodes freed by Delete are reused by later inserts.
+// It keeps the same shape as a TwoThreeTree given the same operations.
+// The zero value is not usable; call NewSlabTree.
+type SlabTree[T any] struct {
+	nodes []slabNode[T]
+
+	// free holds the indexes of nodes freed by Delete, to be reused before the slab grows.
+	free []int32
+
+	// root is slabNil while the tree is empty.
+	root int32
+
+	// comparator is used to compare two values.
+	// returns -1 if a < b, 0 if a == b, 1 if a > b
+	comparator func(T, T) int
+
+	// duplicates decides what Insert does with a value equal to one already in the tree.
+	duplicates DuplicatePolicy
+
+	// size is the number of values stored in the tree.
+	size int
+}
+
+// NewSlabTree is a constructor for an empty slab-allocated two-three tree ordered by the given comparator.
+// The tree allows duplicate values.
+func NewSlabTree[T any](comparator func(a, b T) int) *SlabTree[T] {
+	return NewSlabTreeWithPolicy(comparator, AllowDuplicates)
+}
+
+// NewSlabTreeWithPolicy is a constructor for an empty slab-allocated two-three tree ordered by the given comparator,
+// which handles duplicate values according to the given policy.
+func NewSlabTreeWithPolicy[T any](comparator func(a, b T) int, duplicates DuplicatePolicy) *SlabTree[T] {
+	return &SlabTree[T]{
+		root:       slabNil,
+		comparator: comparator,
+		duplicates: duplicates,
+	}
+}
+
+// Grow reserves room for at least n more values, so that inserting them does not reallocate the slab.
+func (tree *SlabTree[T]) Grow(n int) {
+	// Every node holds at least one value, so n values never need more than n nodes.
+	if need := len(tree.nodes) + n - len(tree.free); need > cap(tree.nodes) {
+		nodes := make([]slabNode[T], len(tree.nodes), need)
+		copy(nodes, tree.nodes)
+		tree.nodes = nodes
+	}
+}
+
+// alloc returns the index of a new, empty node.
+// The slab may be reallocated, so pointers to nodes taken before must not be used after.
+func (tree *SlabTree[T]) alloc(leaf bool) int32 {
+	if n := len(tree.free); n > 0 {
+		i := tree.free[n-1]
+		tree.free = tree.free[:n-1]
+		tree.nodes[i].leaf = leaf
+		return i
+	}
+	tree.nodes = append(tree.nodes, slabNode[T]{children: slabNoChildren, leaf: leaf})
+	return int32(len(tree.nodes) - 1)
+}
+
+// release returns a node to the free list, clearing it so that the values it held can be collected.
+func (tree *SlabTree[T]) release(i int32) {
+	tree.nodes[i] = slabNode[T]{children: slabNoChildren}
+	tree.free = append(tree.free, i)
+}
+
+// lowerBound returns the index of the first value of the node not less than value.
+func (tree *SlabTree[T]) lowerBound(node *slabNode[T], value T) int {
+	return sort.Search(int(node.count), func(i int) bool {
+		return tree.comparator(node.values[i], value) >= 0
+	})
+}
+
+// upperBound returns the index of the first value of the node greater than value.
+func (tree *SlabTree[T]) upperBound(node *slabNode[T], value T) int {
+	return sort.Search(int(node.count), func(i int) bool {
+		return tree.comparator(node.values[i], value) > 0
+	})
+}
+
+// find returns the node holding a value equal to the given one, and its index within the node.
+// If no node holds the value, it returns nil.
+func (tree *SlabTree[T]) find(value T) (*slabNode[T], int) {
+	for i := tree.root; i != slabNil; {
+		node := &tree.nodes[i]
+		j := tree.lowerBound(node, value)
+		if j < int(node.count) && tree.comparator(node.values[j], value) == 0 {
+			return node, j
+		}
+		if node.leaf {
+			break
+		}
+		i = node.children[j]
+	}
+	return nil, 0
+}
+
+// Len returns the number of values stored in the tree.
+func (tree *SlabTree[T]) Len() int {
+	return tree.size
+}
+
+// Height returns the number of levels in the tree, or 0 if the tree is empty.
+func (tree *SlabTree[T]) Height() int {
+	height := 0
+	for i := tree.root; i != slabNil; height++ {
+		if tree.nodes[i].leaf {
+			i = slabNil
+		} else {
+			i = tree.nodes[i].children[0]
+		}
+	}
+	return height
+}
+
+// Get finds a value in the tree.
+// It returns the stored value equal to the given one, and whether it was found.
+func (tree *SlabTree[T]) Get(value T) (T, bool) {
+	var zeroVal T
+	if node, i := tree.find(value); node != nil {
+		return node.values[i], true
+	}
+	return zeroVal, false
+}
+
+// Contains reports whether a value equal to the given one is stored in the tree.
+func (tree *SlabTree[T]) Contains(value T) bool {
+	node, _ := tree.find(value)
+	return node != nil
+}
+
+// insert inserts the value into the subtree rooted at the node at index n, placing it after any values equal to it.
+// If the node overflows it is split, and the index of the new right sibling is returned along with the value
+// that separates them; otherwise the index returned is slabNil.
+func (tree *SlabTree[T]) insert(n int32, value T) (T, int32) {
+	var mid T
+	i := tree.upperBound(&tree.nodes[n], value)
+
+	// Gather the node's values and children with the new one, as the node may have to hold one too many.
+	var values [3]T
+	children := [4]int32{slabNil, slabNil, slabNil, slabNil}
+	node := &tree.nodes[n]
+	count := int(node.count)
+	copy(values[:], node.keys())
+	copy(children[:], node.children[:count+1])
+
+	if node.leaf {
+		copy(values[i+1:], values[i:count])
+		values[i] = value
+	} else {
+		sep, right := tree.insert(node.children[i], value)
+		if right == slabNil {
+			return mid, slabNil
+		}
+		copy(values[i+1:], values[i:count])
+		values[i] = sep
+		copy(children[i+2:], children[i+1:count+1])
+		children[i+1] = right
+	}
+	count++
+
+	// The recursion may have reallocated the slab.
+	node = &tree.nodes[n]
+	if count < 3 {
+		node.values, node.count = [2]T{values[0], values[1]}, int8(count)
+		copy(node.children[:], children[:3])
+		return mid, slabNil
+	}
+
+	// Split around the middle value, which moves up to the parent.
+	leaf := node.leaf
+	right := tree.alloc(leaf)
+	node, sibling := &tree.nodes[n], &tree.nodes[right]
+	node.values, node.count = [2]T{values[0]}, 1
+	sibling.values, sibling.count = [2]T{values[2]}, 1
+	if !leaf {
+		node.children = [3]int32{children[0], children[1], slabNil}
+		sibling.children = [3]int32{children[2], children[3], slabNil}
+	}
+	return values[1], right
+}
+
+// Insert inserts a value into the tree.
+// A value equal to one already in the tree is handled according to the tree's DuplicatePolicy.
+func (tree *SlabTree[T]) Insert(value T) error {
+	if tree.duplicates != AllowDuplicates {
+		if node, i := tree.find(value); node != nil {
+			if tree.duplicates == RejectDuplicates {
+				return ErrDuplicate
+			}
+			node.values[i] = value
+			return nil
+		}
+	}
+
+	if tree.root == slabNil {
+		tree.root = tree.alloc(true)
+		tree.nodes[tree.root].values[0], tree.nodes[tree.root].count = value, 1
+	} else if sep, right := tree.insert(tree.root, value); right != slabNil {
+		root := tree.alloc(false)
+		tree.nodes[root].values[0], tree.nodes[root].count = sep, 1
+		tree.nodes[root].children = [3]int32{tree.root, right, slabNil}
+		tree.root = root
+	}
+	tree.size++
+	return nil
+}
+
+// removeAt removes the value at i of the node, and for an internal node the child after it.
+func (node *slabNode[T]) removeAt(i int) {
+	var zeroVal T
+	copy(node.values[i:], node.values[i+1:node.count])
+	if !node.leaf {
+		copy(node.children[i+1:], node.children[i+2:node.count+1])
+		node.children[node.count] = slabNil
+	}
+	node.count--
+	node.values[node.count] = zeroVal
+}
+
+// repair restores the child at i of the node at index n after it has lost its only value.
+// It borrows a value from an adjacent sibling that holds two, and otherwise merges the child with a sibling,
+// which may leave the node itself without values.
+func (tree *SlabTree[T]) repair(n int32, i int) {
+	var zeroVal T
+	node := &tree.nodes[n]
+	child := &tree.nodes[node.children[i]]
+	if child.count > 0 {
+		return
+	}
+
+	// Borrow from the left sibling, rotating through the separator.
+	if i > 0 && tree.nodes[node.children[i-1]].count == 2 {
+		left := &tree.nodes[node.children[i-1]]
+		child.values[0], child.count = node.values[i-1], 1
+		node.values[i-1], left.values[1] = left.values[1], zeroVal
+		left.count = 1
+		if !left.leaf {
+			child.children[1], child.children[0] = child.children[0], left.children[2]
+			left.children[2] = slabNil
+		}
+		return
+	}
+
+	// Borrow from the right sibling, rotating through the separator.
+	if i < int(node.count) && tree.nodes[node.children[i+1]].count == 2 {
+		right := &tree.nodes[node.children[i+1]]
+		child.values[0], child.count = node.values[i], 1
+		node.values[i] = right.values[0]
+		right.values[0], right.values[1] = right.values[1], zeroVal
+		right.count = 1
+		if !right.leaf {
+			child.children[1] = right.children[0]
+			right.children = [3]int32{right.children[1], right.children[2], slabNil}
+		}
+		return
+	}
+
+	// No sibling can spare a value, so merge with one, pulling the separator down.
+	if i == 0 {
+		i++
+	}
+	left, right := &tree.nodes[node.children[i-1]], &tree.nodes[node.children[i]]
+	count := int(left.count)
+	left.values[count] = node.values[i-1]
+	copy(left.values[count+1:], right.keys())
+	if !left.leaf {
+		copy(left.children[count+1:], right.children[:right.count+1])
+	}
+	left.count += 1 + right.count
+	tree.release(node.children[i])
+
+	node = &tree.nodes[n]
+	node.removeAt(i - 1)
+}
+
+// removeMin removes the smallest value of the subtree rooted at the node at index n, repairing it on the way back up.
+// It returns the removed value.
+func (tree *SlabTree[T]) removeMin(n int32) T {
+	node := &tree.nodes[n]
+	if node.leaf {
+		value := node.values[0]
+		node.removeAt(0)
+		return value
+	}
+	value := tree.removeMin(node.children[0])
+	tree.repair(n, 0)
+	return value
+}
+
+// remove removes a value equal to the given one from the subtree rooted at the node at index n,
+// repairing it on the way back up.
+// It returns whether a value was found.
+func (tree *SlabTree[T]) remove(n int32, value T) bool {
+	node := &tree.nodes[n]
+	i := tree.lowerBound(node, value)
+	if i < int(node.count) && tree.comparator(node.values[i], value) == 0 {
+		if node.leaf {
+			node.removeAt(i)
+			return true
+		}
+
+		// Values are only ever removed from leaves, so an internal value is
+		// replaced by its in-order successor first.
+		node.values[i] = tree.removeMin(node.children[i+1])
+		tree.repair(n, i+1)
+		return true
+	}
+
+	if node.leaf || !tree.remove(node.children[i], value) {
+		return false
+	}
+	tree.repair(n, i)
+	return true
+}
+
+// Delete removes a value from the tree.
+// It returns whether the value was found.
+func (tree *SlabTree[T]) Delete(value T) bool {
+	if tree.root == slabNil || !tree.remove(tree.root, value) {
+		return false
+	}
+
+	if root := &tree.nodes[tree.root]; root.count == 0 {
+		old := tree.root
+		if root.leaf {
+			tree.root = slabNil
+		} else {
+			tree.root = root.children[0]
+		}
+		tree.release(old)
+	}
+	tree.size--
+	return true
+}
+
+// Clear removes every value from the tree, keeping the slab for reuse.
+func (tree *SlabTree[T]) Clear() {
+	var zeroVal slabNode[T]
+	for i := range tree.nodes {
+		tree.nodes[i] = zeroVal
+	}
+	tree.nodes, tree.free = tree.nodes[:0], tree.free[:0]
+	tree.root, tree.size = slabNil, 0
+}
+
+// ascend calls fn, in ascending order, for each value of the subtree rooted at the node at index n.
+// It returns false once fn returns false.
+func (tree *SlabTree[T]) ascend(n int32, fn func(T) bool) bool {
+	node := &tree.nodes[n]
+	for i := 0; i < int(node.count); i++ {
+		if !node.leaf && !tree.ascend(node.children[i], fn) {
+			return false
+		}
+		if !fn(node.values[i]) {
+			return false
+		}
+	}
+	return node.leaf || tree.ascend(node.children[node.count], fn)
+}
+
+// Ascend calls fn for each value of the tree in ascending order.
+// Iteration stops early if fn returns false.
+func (tree *SlabTree[T]) Ascend(fn func(T) bool) {
+	if tree.root != slabNil {
+		tree.ascend(tree.root, fn)
+	}
+}
+
+// InOrder returns the values of the tree in ascending order.
+func (tree *SlabTree[T]) InOrder() []T {
+	result := make([]T, 0, tree.size)
+	tree.Ascend(func(value T) bool {
+		result = append(result, value)
+		return true
+	})
+	return result
+}
+
+// BFS traverses the tree in breadth-first order.
+// It returns the values of each node in the order visited.
+func (tree *SlabTree[T]) BFS() []T {
+	var result []T
+	if tree.root == slabNil {
+		return result
+	}
+	queue := []int32{tree.root}
+	for len(queue) > 0 {
+		node := &tree.nodes[queue[0]]
+		queue = queue[1:]
+		result = append(result, node.keys()...)
+		if !node.leaf {
+			queue = append(queue, node.children[:node.count+1]...)
+		}
+	}
+	return result
+}
+
+// validate checks the subtree rooted at the node at index n, whose values must lie between lo and hi (when not nil).
+func (tree *SlabTree[T]) validate(v *validator[T], n int32, path string, depth int, lo, hi *T) {
+	if n < 0 || int(n) >= len(tree.nodes) {
+		v.report(path, "index %d is outside the slab of %d node(s)", n, len(tree.nodes))
+		return
+	}
+	node := &tree.nodes[n]
+	v.count += int(node.count)
+
+	if node.count < 1 || node.count > 2 {
+		v.report(path, "has %d value(s), want 1 or 2", node.count)
+		return
+	}
+	children := int(node.count) + 1
+	if node.leaf {
+		children = 0
+	}
+	for i := children; i < len(node.children); i++ {
+		if node.children[i] != slabNil {
+			v.report(path, "unused child slot %d holds index %d", i, node.children[i])
+		}
+	}
+
+	for i, value := range node.keys() {
+		if i > 0 && tree.comparator(node.values[i-1], value) > 0 {
+			v.report(path, "values %v and %v are out of order", node.values[i-1], value)
+		}
+		if lo != nil && tree.comparator(value, *lo) < 0 {
+			v.report(path, "value %v is less than the separator %v above it", value, *lo)
+		}
+		if hi != nil && tree.comparator(value, *hi) > 0 {
+			v.report(path, "value %v is greater than the separator %v above it", value, *hi)
+		}
+	}
+
+	if node.leaf {
+		if v.leafDepth < 0 {
+			v.leafDepth = depth
+		} else if depth != v.leafDepth {
+			v.report(path, "leaf is at depth %d, want %d", depth, v.leafDepth)
+		}
+		return
+	}
+
+	for i := 0; i <= int(node.count); i++ {
+		childLo, childHi := lo, hi
+		if i > 0 {
+			childLo = &node.values[i-1]
+		}
+		if i < int(node.count) {
+			childHi = &node.values[i]
+		}
+		tree.validate(v, node.children[i], fmt.Sprintf("%s.%d", path, i), depth+1, childLo, childHi)
+	}
+}
+
+// Validate checks every structural invariant of the tree, as well as its element count.
+// It returns nil if the tree is valid, or a *ValidationError listing every violation.
+func (tree *SlabTree[T]) Validate() error {
+	v := &validator[T]{leafDepth: -1}
+	if tree.root != slabNil {
+		tree.validate(v, tree.root, "root", 0, nil, nil)
+	}
+	if v.count != tree.size {
+		v.report("root", "tree holds %d value(s), but Len is %d", v.count, tree.size)
+	}
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+	return nil
+}
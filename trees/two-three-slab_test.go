@@ -0,0 +1,225 @@
+package trees
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSlabTreeMatchesTwoThreeTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(25))
+	twoThree := NewTwoThreeTree(intComparator)
+	slab := NewSlabTree(intComparator)
+
+	for i := 0; i < 5000; i++ {
+		value := rng.Intn(300)
+		if rng.Intn(3) == 0 {
+			found, err := twoThree.Delete(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := slab.Delete(value); got != found {
+				t.Fatalf("Delete(%d) = %v, want %v", value, got, found)
+			}
+		} else {
+			if err := twoThree.Insert(value); err != nil {
+				t.Fatal(err)
+			}
+			if err := slab.Insert(value); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if got, want := slab.BFS(), BFS(twoThree.Root()); !reflect.DeepEqual(got, want) {
+			t.Fatalf("after step %d, BFS() = %v, want %v", i, got, want)
+		}
+		if slab.Height() != twoThree.Height() || slab.Len() != twoThree.Len() {
+			t.Fatalf("after step %d, Height() = %d and Len() = %d, want %d and %d",
+				i, slab.Height(), slab.Len(), twoThree.Height(), twoThree.Len())
+		}
+		if err := slab.Validate(); err != nil {
+			t.Fatalf("after step %d, %v", i, err)
+		}
+	}
+
+	if got, want := slab.InOrder(), InOrder(twoThree.Root()); !reflect.DeepEqual(got, want) {
+		t.Errorf("InOrder() = %v, want %v", got, want)
+	}
+	if len(slab.nodes)-len(slab.free) > slab.Len() {
+		t.Errorf("slab has %d node(s) in use for %d value(s)", len(slab.nodes)-len(slab.free), slab.Len())
+	}
+}
+
+func TestSlabTreeReusesFreedNodes(t *testing.T) {
+	slab := NewSlabTree(intComparator)
+	for i := 0; i < 1000; i++ {
+		slab.Insert(i)
+	}
+	nodes := len(slab.nodes)
+
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 1000; i++ {
+			slab.Delete(i)
+		}
+		if slab.Len() != 0 || slab.Height() != 0 {
+			t.Fatalf("after deleting every value, Len() = %d and Height() = %d, want 0", slab.Len(), slab.Height())
+		}
+		for i := 0; i < 1000; i++ {
+			slab.Insert(i)
+		}
+	}
+	if len(slab.nodes) != nodes {
+		t.Errorf("slab grew to %d node(s), want the %d freed to be reused", len(slab.nodes), nodes)
+	}
+
+	slab.Clear()
+	if slab.Len() != 0 || len(slab.nodes) != 0 || slab.Contains(1) {
+		t.Errorf("Clear() left Len() = %d with %d node(s)", slab.Len(), len(slab.nodes))
+	}
+}
+
+func TestSlabTreeGrow(t *testing.T) {
+	slab := NewSlabTree(intComparator)
+	slab.Grow(1000)
+	allocs := testing.AllocsPerRun(10, func() {
+		// Clear keeps the slab, so every run reuses the nodes reserved by Grow.
+		slab.Clear()
+		for i := 0; i < 1000; i++ {
+			slab.Insert(i)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("inserting 1000 values after Grow(1000) made %v allocation(s), want 0", allocs)
+	}
+}
+
+func TestSlabTreePolicies(t *testing.T) {
+	reject := NewSlabTreeWithPolicy(recordComparator, RejectDuplicates)
+	if err := reject.Insert(record{key: 1, id: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reject.Insert(record{key: 1, id: 2}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Insert() error = %v, want %v", err, ErrDuplicate)
+	}
+
+	replace := NewSlabTreeWithPolicy(recordComparator, ReplaceDuplicates)
+	for _, r := range []record{{key: 1, id: 1}, {key: 2, id: 2}, {key: 1, id: 3}} {
+		if err := replace.Insert(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, _ := replace.Get(record{key: 1}); got.id != 3 || replace.Len() != 2 {
+		t.Errorf("Get() = %v with Len() = %d, want id 3 with 2", got, replace.Len())
+	}
+
+	if _, ok := NewSlabTree(intComparator).Get(1); ok {
+		t.Error("Get() on an empty tree found a value")
+	}
+}
+
+func TestSlabTreeValidateReportsViolations(t *testing.T) {
+	slab := NewSlabTree(intComparator)
+	for i := 0; i < 10; i++ {
+		slab.Insert(i)
+	}
+	root := &slab.nodes[slab.root]
+	root.values[0], root.values[1] = root.values[1], root.values[0]
+	slab.size++
+
+	var verr *ValidationError
+	if err := slab.Validate(); !errors.As(err, &verr) || len(verr.Violations) < 2 {
+		t.Errorf("Validate() = %v, want violations for the order and the count", err)
+	}
+}
+
+func TestSlabTreeValidateReportsDeadChildSlots(t *testing.T) {
+	slab := NewSlabTree(intComparator)
+	for i := 0; i < 10; i++ {
+		slab.Insert(i)
+	}
+	for i := 0; i < 10; i += 2 {
+		slab.Delete(i)
+	}
+	if err := slab.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Index 0 is a node, so a dead slot holding it would look like a link.
+	for i := range slab.nodes {
+		if slab.nodes[i].leaf && slab.nodes[i].count > 0 {
+			slab.nodes[i].children[0] = 0
+			break
+		}
+	}
+	var verr *ValidationError
+	if err := slab.Validate(); !errors.As(err, &verr) || len(verr.Violations) != 1 {
+		t.Errorf("Validate() = %v, want one violation for the dead child slot", err)
+	}
+}
+
+// The benchmarks below compare a SlabTree with a TwoThreeTree. Run them with -benchmem to see the allocations
+// per insert; the GC benchmarks report the time a full collection takes while a large tree is live.
+
+func BenchmarkSlabTreeInsert(b *testing.B) {
+	b.ReportAllocs()
+	rng := rand.New(rand.NewSource(1))
+	tree := NewSlabTree(intComparator)
+	for i := 0; i < b.N; i++ {
+		tree.Insert(rng.Int())
+	}
+}
+
+func BenchmarkTwoThreeTreeInsert(b *testing.B) {
+	b.ReportAllocs()
+	rng := rand.New(rand.NewSource(1))
+	tree := NewTwoThreeTree(intComparator)
+	for i := 0; i < b.N; i++ {
+		tree.Insert(rng.Int())
+	}
+}
+
+// benchmarkGC measures full collections while the tree built by build is live.
+func benchmarkGC(b *testing.B, build func(n int) any) {
+	tree := build(1_000_000)
+	runtime.GC()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+	}
+	b.StopTimer()
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(tree)
+
+	b.ReportMetric(float64(elapsed.Nanoseconds())/float64(b.N), "gc-ns/op")
+	b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N), "pause-ns/op")
+	b.ReportMetric(float64(after.HeapObjects), "heap-objects")
+}
+
+func BenchmarkSlabTreeGC(b *testing.B) {
+	benchmarkGC(b, func(n int) any {
+		tree := NewSlabTree(intComparator)
+		tree.Grow(n)
+		for i := 0; i < n; i++ {
+			tree.Insert(i)
+		}
+		return tree
+	})
+}
+
+func BenchmarkTwoThreeTreeGC(b *testing.B) {
+	benchmarkGC(b, func(n int) any {
+		tree := NewTwoThreeTree(intComparator)
+		for i := 0; i < n; i++ {
+			tree.Insert(i)
+		}
+		return tree
+	})
+}
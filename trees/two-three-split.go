@@ -0,0 +1,171 @@
+package trees
+
+import "fmt"
+
+// newLeaf returns a detached leaf holding the given data, with the comparator and duplicate policy of like.
+// It returns nil if there is no data.
+func newLeaf[T any](data []*T, like *TwoThreeNode[T]) *TwoThreeNode[T] {
+	if len(data) == 0 {
+		return nil
+	}
+	leaf := &TwoThreeNode[T]{
+		comparator: like.comparator,
+		duplicates: like.duplicates,
+		height:     1,
+		size:       len(data),
+	}
+	setNodeData(leaf, data)
+	return leaf
+}
+
+// join3 joins two detached trees and a separator, where every value of left is at most sep
+// and every value of right is at least sep. Either tree may be nil.
+// The shorter tree is grafted onto the spine of the taller one at the level where their heights match,
+// so the cost is proportional to the difference in their heights.
+// It returns the root of the joined tree.
+func join3[T any](left *TwoThreeNode[T], sep *T, right *TwoThreeNode[T], like *TwoThreeNode[T]) *TwoThreeNode[T] {
+	switch {
+	case left == nil && right == nil:
+		return newLeaf([]*T{sep}, like)
+	case left == nil:
+		leaf := leftmostLeaf(right)
+		return settle(leaf, insertAt(nodeData(leaf), 0, sep), nil)
+	case right == nil:
+		leaf := rightmostLeaf(left)
+		return settle(leaf, append(nodeData(leaf), sep), nil)
+	}
+
+	if left.height == right.height {
+		root := &TwoThreeNode[T]{
+			firstData:  sep,
+			comparator: left.comparator,
+			duplicates: left.duplicates,
+			height:     left.height + 1,
+		}
+		setNodeChildren(root, []*TwoThreeNode[T]{left, right})
+		resize(root)
+		return root
+	}
+
+	if left.height > right.height {
+		// Graft right as the last child of the node on the right spine of left one level above it.
+		node := left
+		for node.height > right.height+1 {
+			children := nodeChildren(node)
+			node = children[len(children)-1]
+		}
+		return settle(node, append(nodeData(node), sep), append(nodeChildren(node), right))
+	}
+
+	// Graft left as the first child of the node on the left spine of right one level above it.
+	node := right
+	for node.height > left.height+1 {
+		node = node.firstChild
+	}
+	return settle(node, insertAt(nodeData(node), 0, sep), insertAt(nodeChildren(node), 0, left))
+}
+
+// detach removes the parent pointer of node, so it can be treated as the root of its own tree.
+// It returns node.
+func detach[T any](node *TwoThreeNode[T]) *TwoThreeNode[T] {
+	if node != nil {
+		node.parent = nil
+	}
+	return node
+}
+
+// split splits the detached subtree rooted at node around the pivot.
+// It returns the roots of the values less than the pivot, and of the values greater than or equal to it.
+func split[T any](node *TwoThreeNode[T], pivot T) (*TwoThreeNode[T], *TwoThreeNode[T]) {
+	data := nodeData(node)
+	children := nodeChildren(node)
+
+	// data[i:] all belong to the right, and children[i] straddles the pivot.
+	i := len(data)
+	for j, datum := range data {
+		if node.comparator(pivot, *datum) <= 0 {
+			i = j
+			break
+		}
+	}
+
+	if len(children) == 0 {
+		return newLeaf(data[:i], node), newLeaf(data[i:], node)
+	}
+
+	left, right := split(detach(children[i]), pivot)
+
+	// Join the subtrees and separators left of the path onto left, and those right of it onto right.
+	for j := i - 1; j >= 0; j-- {
+		left = join3(detach(children[j]), data[j], left, node)
+	}
+	for j := i; j < len(data); j++ {
+		right = join3(right, data[j], detach(children[j+1]), node)
+	}
+
+	return left, right
+}
+
+// Split splits the tree around the pivot, in time proportional to its height.
+// The nodes of the tree are reused, so the original root must not be used afterwards.
+// It returns the root of a tree holding the values less than the pivot, and the root of a tree
+// holding the values greater than or equal to it. Either is nil when it holds no values.
+func Split[T any](root *TwoThreeNode[T], pivot T) (*TwoThreeNode[T], *TwoThreeNode[T]) {
+	if root == nil || root.firstData == nil {
+		return nil, nil
+	}
+	return split(root, pivot)
+}
+
+// Join concatenates two trees, where every value of left is less than or equal to every value of right,
+// in time proportional to their heights.
+// The nodes of both trees are reused, so the original roots must not be used afterwards.
+// It returns the root of the joined tree, or an error wrapping ErrNotSorted if the trees overlap.
+func Join[T any](left, right *TwoThreeNode[T]) (*TwoThreeNode[T], error) {
+	if left == nil || left.firstData == nil {
+		return right, nil
+	}
+	if right == nil || right.firstData == nil {
+		return left, nil
+	}
+
+	maxLeft, _ := Max(left)
+	minRight, _ := Min(right)
+	if left.comparator(maxLeft, minRight) > 0 {
+		return nil, fmt.Errorf("%w: cannot join a tree with maximum %v before a tree with minimum %v", ErrNotSorted, maxLeft, minRight)
+	}
+
+	// The largest value of left becomes the separator between the two trees.
+	leaf := rightmostLeaf(left)
+	data := nodeData(leaf)
+	sep := data[len(data)-1]
+	left = removeDatum(leaf, len(data)-1)
+
+	return join3(left, sep, right, right), nil
+}
+
+// Split moves the values greater than or equal to the pivot out of the tree.
+// It returns a new tree holding the values moved.
+func (tree *TwoThreeTree[T]) Split(pivot T) *TwoThreeTree[T] {
+	left, right := Split(tree.root, pivot)
+
+	other := NewTwoThreeTreeWithPolicy(tree.comparator, tree.duplicates)
+	tree.root, other.root = left, right
+	tree.size, other.size = childSize(left), childSize(right)
+	return other
+}
+
+// Join moves every value of other to the end of the tree, leaving other empty.
+// Every value of the tree must be less than or equal to every value of other.
+// It returns an error wrapping ErrNotSorted if the trees overlap, in which case neither tree is changed.
+func (tree *TwoThreeTree[T]) Join(other *TwoThreeTree[T]) error {
+	root, err := Join(tree.root, other.root)
+	if err != nil {
+		return err
+	}
+
+	tree.root = root
+	tree.size += other.size
+	other.Clear()
+	return nil
+}
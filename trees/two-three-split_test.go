@@ -0,0 +1,114 @@
+package trees
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for _, n := range []int{1, 2, 3, 10, 100, 500} {
+		for _, pivot := range []int{-1, 0, n / 3, n / 2, n - 1, n, n + 1} {
+			root := buildIntTree(t, r.Perm(n)...)
+			left, right := Split(root, pivot)
+
+			var wantLeft, wantRight []int
+			for i := 0; i < n; i++ {
+				if i < pivot {
+					wantLeft = append(wantLeft, i)
+				} else {
+					wantRight = append(wantRight, i)
+				}
+			}
+
+			checkShape(t, left)
+			checkShape(t, right)
+			if got := InOrder(left); !reflect.DeepEqual(got, wantLeft) {
+				t.Fatalf("Split(%d values, %d) left = %v, want %v", n, pivot, got, wantLeft)
+			}
+			if got := InOrder(right); !reflect.DeepEqual(got, wantRight) {
+				t.Fatalf("Split(%d values, %d) right = %v, want %v", n, pivot, got, wantRight)
+			}
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	sizes := []int{0, 1, 2, 5, 30, 200}
+
+	for _, leftSize := range sizes {
+		for _, rightSize := range sizes {
+			var left, right *TwoThreeNode[int]
+			if leftSize > 0 {
+				left = buildIntTree(t, r.Perm(leftSize)...)
+			}
+			if rightSize > 0 {
+				values := r.Perm(rightSize)
+				for i := range values {
+					values[i] += leftSize
+				}
+				right = buildIntTree(t, values...)
+			}
+
+			joined, err := Join(left, right)
+			if err != nil {
+				t.Fatalf("Join() error = %v", err)
+			}
+
+			checkShape(t, joined)
+			got := InOrder(joined)
+			if len(got) != leftSize+rightSize {
+				t.Fatalf("Join(%d, %d values) holds %d values", leftSize, rightSize, len(got))
+			}
+			for i, value := range got {
+				if value != i {
+					t.Fatalf("Join(%d, %d values) = %v", leftSize, rightSize, got)
+				}
+			}
+		}
+	}
+}
+
+func TestJoinOverlapping(t *testing.T) {
+	left := buildIntTree(t, 1, 2, 3)
+	right := buildIntTree(t, 2, 4, 6)
+
+	if _, err := Join(left, right); !errors.Is(err, ErrNotSorted) {
+		t.Errorf("Join() error = %v, want ErrNotSorted", err)
+	}
+	if got := InOrder(left); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Join() changed left to %v", got)
+	}
+}
+
+func TestTwoThreeTreeSplitAndJoin(t *testing.T) {
+	tree := NewTwoThreeTree(intComparator)
+	for _, value := range rand.New(rand.NewSource(1)).Perm(100) {
+		tree.Insert(value)
+	}
+
+	upper := tree.Split(60)
+	if err := tree.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := upper.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if tree.Len() != 60 || upper.Len() != 40 {
+		t.Fatalf("Split() left %d and %d values, want 60 and 40", tree.Len(), upper.Len())
+	}
+
+	if err := tree.Join(upper); err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if err := tree.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if tree.Len() != 100 || upper.Len() != 0 {
+		t.Errorf("Join() left %d and %d values, want 100 and 0", tree.Len(), upper.Len())
+	}
+}
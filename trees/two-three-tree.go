@@ -0,0 +1,125 @@
+package trees
+
+// TwoThreeTree owns the root of a two-three tree and the comparator used to order its values.
+// Unlike a bare *TwoThreeNode, whose identity changes as the tree is rebalanced,
+// a *TwoThreeTree is a stable handle. The zero value is not usable; call NewTwoThreeTree.
+type TwoThreeTree[T any] struct {
+	// root is nil while the tree is empty.
+	root *TwoThreeNode[T]
+
+	// comparator is used to compare two values.
+	// returns -1 if a < b, 0 if a == b, 1 if a > b
+	comparator func(T, T) int
+
+	// duplicates decides what Insert does with a value equal to one already in the tree.
+	duplicates DuplicatePolicy
+
+	// size is the number of values stored in the tree.
+	size int
+
+	// jsonForm is the form MarshalJSON encodes the tree in.
+	jsonForm JSONForm
+
+	// onUpdate, if not nil, is called by Insert and Delete on every node whose data or children they change,
+	// children before their parents, so that whatever is kept about a subtree can be brought up to date.
+	onUpdate func(node *TwoThreeNode[T])
+}
+
+// NewTwoThreeTree is a constructor for an empty two-three tree ordered by the given comparator.
+// The tree allows duplicate values.
+func NewTwoThreeTree[T any](comparator func(a, b T) int) *TwoThreeTree[T] {
+	return NewTwoThreeTreeWithPolicy(comparator, AllowDuplicates)
+}
+
+// NewTwoThreeTreeWithPolicy is a constructor for an empty two-three tree ordered by the given comparator,
+// which handles duplicate values according to the given policy.
+func NewTwoThreeTreeWithPolicy[T any](comparator func(a, b T) int, duplicates DuplicatePolicy) *TwoThreeTree[T] {
+	return &TwoThreeTree[T]{
+		root:       nil,
+		comparator: comparator,
+		duplicates: duplicates,
+		size:       0,
+	}
+}
+
+// Root returns the root node of the tree, or nil if the tree is empty.
+// The root changes as values are inserted and deleted, so it should not be retained.
+func (tree *TwoThreeTree[T]) Root() *TwoThreeNode[T] {
+	return tree.root
+}
+
+// layout returns the layout the tree is balanced through.
+func (tree *TwoThreeTree[T]) layout() twoThreeLayout[T] {
+	return twoThreeLayout[T]{onUpdate: tree.onUpdate}
+}
+
+// Insert inserts a value into the tree.
+// A value equal to one already in the tree is handled according to the tree's DuplicatePolicy.
+func (tree *TwoThreeTree[T]) Insert(value T) error {
+	if tree.root == nil {
+		tree.root = NewWithPolicy(value, tree.comparator, tree.duplicates)
+		tree.layout().update(tree.root)
+		tree.size++
+		return nil
+	}
+
+	root, replaced, err := insert(tree.layout(), tree.root, value)
+	if err != nil {
+		return err
+	}
+
+	tree.root = root
+	if !replaced {
+		tree.size++
+	}
+	return nil
+}
+
+// Delete removes a value from the tree.
+// It returns whether the value was found.
+func (tree *TwoThreeTree[T]) Delete(value T) (bool, error) {
+	root, found, err := deleteValue(tree.layout(), tree.root, value)
+	if err != nil {
+		return false, err
+	}
+
+	tree.root = root
+	if found {
+		tree.size--
+	}
+	return found, nil
+}
+
+// Get finds a value in the tree.
+// It returns the stored value equal to the given one, and whether it was found.
+func (tree *TwoThreeTree[T]) Get(value T) (T, bool) {
+	var zeroVal T
+	if found := Get(tree.root, value); found != nil {
+		return *found, true
+	}
+	return zeroVal, false
+}
+
+// Contains reports whether a value equal to the given one is stored in the tree.
+func (tree *TwoThreeTree[T]) Contains(value T) bool {
+	return Get(tree.root, value) != nil
+}
+
+// Len returns the number of values stored in the tree.
+func (tree *TwoThreeTree[T]) Len() int {
+	return tree.size
+}
+
+// Height returns the number of levels in the tree, or 0 if the tree is empty.
+func (tree *TwoThreeTree[T]) Height() int {
+	if tree.root == nil {
+		return 0
+	}
+	return tree.root.height
+}
+
+// Clear removes all values from the tree.
+func (tree *TwoThreeTree[T]) Clear() {
+	tree.root = nil
+	tree.size = 0
+}
@@ -0,0 +1,86 @@
+package trees
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTwoThreeTreeEmpty(t *testing.T) {
+	tree := NewTwoThreeTree(intComparator)
+
+	if got := tree.Len(); got != 0 {
+		t.Errorf("Len() = %v, want 0", got)
+	}
+	if got := tree.Height(); got != 0 {
+		t.Errorf("Height() = %v, want 0", got)
+	}
+	if got, found := tree.Get(1); found || got != 0 {
+		t.Errorf("Get() = %v, %v, want 0, false", got, found)
+	}
+	if tree.Contains(1) {
+		t.Errorf("Contains() = true, want false")
+	}
+	if found, err := tree.Delete(1); found || err != nil {
+		t.Errorf("Delete() = %v, %v, want false, nil", found, err)
+	}
+}
+
+func TestTwoThreeTree(t *testing.T) {
+	tree := NewTwoThreeTree(intComparator)
+	values := []int{10, 20, 5, 7, 25, 35, 40, 45, 8, 15, 17}
+
+	for _, value := range values {
+		if err := tree.Insert(value); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+
+	if got := tree.Len(); got != len(values) {
+		t.Errorf("Len() = %v, want %v", got, len(values))
+	}
+	if got := tree.Height(); got != 3 {
+		t.Errorf("Height() = %v, want 3", got)
+	}
+	for _, value := range values {
+		if got, found := tree.Get(value); !found || got != value {
+			t.Errorf("Get(%v) = %v, %v, want %v, true", value, got, found, value)
+		}
+	}
+	if tree.Contains(6) {
+		t.Errorf("Contains(6) = true, want false")
+	}
+
+	if found, err := tree.Delete(25); !found || err != nil {
+		t.Errorf("Delete() = %v, %v, want true, nil", found, err)
+	}
+	if got := tree.Len(); got != len(values)-1 {
+		t.Errorf("Len() = %v, want %v", got, len(values)-1)
+	}
+	if tree.Contains(25) {
+		t.Errorf("Contains(25) = true, want false")
+	}
+
+	tree.Clear()
+	if got := tree.Len(); got != 0 || tree.Root() != nil {
+		t.Errorf("Clear() left %v values", got)
+	}
+}
+
+func TestTwoThreeNodeIntNil(t *testing.T) {
+	root := TwoThreeNodeInt(nil)
+
+	if got := Get(root, 1); got != nil {
+		t.Errorf("Get() = %v, want nil", got)
+	}
+	if got := BFS(root); len(got) != 0 {
+		t.Errorf("BFS() = %v, want []", got)
+	}
+
+	root, err := Insert(root, 1)
+	if err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+	if got := BFS(root); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("BFS() = %v, want [1]", got)
+	}
+}
@@ -0,0 +1,163 @@
+package trees
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Violation is a single broken invariant found by Validate.
+type Violation struct {
+	// Path locates the offending node: "root", then the index of each child followed, e.g. "root.2.0".
+	Path string
+
+	// Reason describes the broken invariant.
+	Reason string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Reason)
+}
+
+// ValidationError is returned by Validate, and holds every violation found in the tree.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		lines[i] = violation.Error()
+	}
+	return fmt.Sprintf("tree has %d violation(s):\n\t%s", len(e.Violations), strings.Join(lines, "\n\t"))
+}
+
+// validator accumulates the violations found while walking a tree.
+type validator[T any] struct {
+	violations []Violation
+
+	// leafDepth is the depth of the first leaf visited, or -1 before any leaf is visited.
+	leafDepth int
+
+	// count is the number of values visited.
+	count int
+}
+
+func (v *validator[T]) report(path string, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: path, Reason: fmt.Sprintf(format, args...)})
+}
+
+// validate checks the subtree rooted at node, whose values must lie between lo and hi (when not nil).
+// It returns the actual height and size of the subtree.
+func (v *validator[T]) validate(node *TwoThreeNode[T], path string, depth int, lo, hi *T) (int, int) {
+	data := nodeData(node)
+	children := nodeChildren(node)
+	v.count += len(data)
+
+	if node.firstData == nil && node.secondData != nil {
+		v.report(path, "has a second value but no first value")
+	}
+	if len(data) == 0 {
+		v.report(path, "has no values")
+	}
+	if node.comparator == nil {
+		v.report(path, "has no comparator")
+		return node.height, node.size
+	}
+
+	if !isLeaf(*node) {
+		if _, err := nodeType(*node); err != nil {
+			v.report(path, "has %d value(s) and %d child(ren): %v", len(data), len(children), err)
+		}
+	}
+
+	for i, datum := range data {
+		if i > 0 && node.comparator(*data[i-1], *datum) > 0 {
+			v.report(path, "values %v and %v are out of order", *data[i-1], *datum)
+		}
+		if lo != nil && node.comparator(*datum, *lo) < 0 {
+			v.report(path, "value %v is less than the separator %v above it", *datum, *lo)
+		}
+		if hi != nil && node.comparator(*datum, *hi) > 0 {
+			v.report(path, "value %v is greater than the separator %v above it", *datum, *hi)
+		}
+	}
+
+	if len(children) == 0 {
+		if v.leafDepth < 0 {
+			v.leafDepth = depth
+		} else if depth != v.leafDepth {
+			v.report(path, "leaf is at depth %d, want %d", depth, v.leafDepth)
+		}
+	}
+
+	height, size := 0, len(data)
+	for i, child := range children {
+		childPath := fmt.Sprintf("%s.%d", path, i)
+		if child.parent != node {
+			v.report(childPath, "parent pointer does not point to its parent")
+		}
+
+		childLo, childHi := lo, hi
+		if i > 0 && i-1 < len(data) {
+			childLo = data[i-1]
+		}
+		if i < len(data) {
+			childHi = data[i]
+		}
+
+		childHeight, childSize := v.validate(child, childPath, depth+1, childLo, childHi)
+		if childHeight > height {
+			height = childHeight
+		}
+		size += childSize
+	}
+	height++
+
+	if node.height != height {
+		v.report(path, "height is %d, want %d", node.height, height)
+	}
+	if node.size != size {
+		v.report(path, "size is %d, want %d", node.size, size)
+	}
+
+	return height, size
+}
+
+// Validate walks the whole tree and checks every structural invariant of a two-three tree:
+// each node has one or two values and either no children or one more child than values,
+// all leaves are at the same depth, values are ordered within their node and relative to the separators above them,
+// heights and subtree sizes are up to date, and every child points back to its parent.
+// It returns nil if the tree is valid, or a *ValidationError listing every violation.
+// A nil root, or a root without values or children, is a valid empty tree.
+func Validate[T any](root *TwoThreeNode[T]) error {
+	v := &validator[T]{leafDepth: -1}
+	v.validateRoot(root)
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+	return nil
+}
+
+func (v *validator[T]) validateRoot(root *TwoThreeNode[T]) {
+	if root == nil || (root.firstData == nil && root.secondData == nil && isLeaf(*root)) {
+		return
+	}
+	if root.parent != nil {
+		v.report("root", "root has a parent")
+	}
+	v.validate(root, "root", 0, nil, nil)
+}
+
+// Validate checks every structural invariant of the tree, as well as its element count.
+// It returns nil if the tree is valid, or a *ValidationError listing every violation.
+func (tree *TwoThreeTree[T]) Validate() error {
+	v := &validator[T]{leafDepth: -1}
+	v.validateRoot(tree.root)
+	if v.count != tree.size {
+		v.report("root", "tree holds %d value(s), but Len is %d", v.count, tree.size)
+	}
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package trees
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		breakTree  func(root *TwoThreeNode[int])
+		wantPaths  []string
+		wantReason string
+	}{
+		{
+			name:      "It accepts a valid tree",
+			breakTree: func(root *TwoThreeNode[int]) {},
+		},
+		{
+			name: "It reports a stale height",
+			breakTree: func(root *TwoThreeNode[int]) {
+				root.secondChild.height = 7
+			},
+			wantPaths:  []string{"root.1"},
+			wantReason: "height is 7, want 2",
+		},
+		{
+			name: "It reports a broken parent pointer",
+			breakTree: func(root *TwoThreeNode[int]) {
+				root.firstChild.secondChild.parent = root
+			},
+			wantPaths:  []string{"root.0.1"},
+			wantReason: "parent pointer",
+		},
+		{
+			name: "It reports a value out of order relative to its separators",
+			breakTree: func(root *TwoThreeNode[int]) {
+				*root.thirdChild.firstChild.firstData = 1
+			},
+			wantPaths:  []string{"root.2.0"},
+			wantReason: "less than the separator",
+		},
+		{
+			name: "It reports a wrong child count and leaves at different depths",
+			breakTree: func(root *TwoThreeNode[int]) {
+				root.secondChild.secondChild = nil
+				root.secondChild.firstChild = nil
+			},
+			wantPaths:  []string{"root.1", "root.1"},
+			wantReason: "leaf is at depth 1, want 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// (10, 25) / (7) (17) (40) / (5) (8) (15) (20) (35) (45)
+			root := buildIntTree(t, 10, 20, 5, 7, 25, 35, 40, 45, 8, 15, 17)
+			tt.breakTree(root)
+
+			err := Validate(root)
+			if len(tt.wantPaths) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want a *ValidationError", err)
+			}
+			if len(validationErr.Violations) < len(tt.wantPaths) {
+				t.Fatalf("Validate() found %v, want at least %d violation(s)", err, len(tt.wantPaths))
+			}
+			for i, path := range tt.wantPaths {
+				if got := validationErr.Violations[i].Path; got != path {
+					t.Errorf("Violations[%d].Path = %v, want %v", i, got, path)
+				}
+			}
+			if !strings.Contains(err.Error(), tt.wantReason) {
+				t.Errorf("Validate() error = %v, want it to mention %q", err, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestValidateEmpty(t *testing.T) {
+	if err := Validate[int](nil); err != nil {
+		t.Errorf("Validate(nil) error = %v, want nil", err)
+	}
+	if err := Validate(TwoThreeNodeInt(nil)); err != nil {
+		t.Errorf("Validate() of an empty root error = %v, want nil", err)
+	}
+	if err := NewTwoThreeTree(intComparator).Validate(); err != nil {
+		t.Errorf("Validate() of an empty tree error = %v, want nil", err)
+	}
+}
+
+func TestTwoThreeTreeValidateCount(t *testing.T) {
+	tree := NewTwoThreeTree(intComparator)
+	for i := 0; i < 10; i++ {
+		tree.Insert(i)
+	}
+	if err := tree.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v, want nil", err)
+	}
+
+	tree.size = 3
+	if err := tree.Validate(); err == nil || !strings.Contains(err.Error(), "Len is 3") {
+		t.Errorf("Validate() error = %v, want a count mismatch", err)
+	}
+}
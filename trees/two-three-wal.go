@@ -0,0 +1,410 @@
+package trees
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+/* A DurableTree keeps two files in its directory. The snapshot holds the tree as of a checkpoint:
+
+	lsn        uint64   the sequence number of the last record the snapshot includes
+	tree                the values, in the binary format written by Encode
+
+and the log holds every change made since, as a sequence of records:
+
+	length     uint32   the length of the body
+	checksum   uint32   the CRC-32 (IEEE) of the body
+	body:
+	  lsn      uint64   the sequence number of the record, one more than the record before it
+	  op       byte     walInsert or walDelete
+	  value             the value's bytes from the Codec
+
+Fixed-width integers are big-endian.
+*/
+
+const (
+	walSnapshotName = "snapshot"
+	walLogName      = "wal.log"
+
+	walFrameHeaderSize = 4 + 4
+	walBodyHeaderSize  = 8 + 1
+
+	walInsert byte = 1
+	walDelete byte = 2
+)
+
+// errTornRecord marks a log record cut short or damaged, from which recovery discards the rest of the log.
+var errTornRecord = errors.New("torn log record")
+
+// DurableOptions configures a DurableTree opened by OpenDurableTree.
+type DurableOptions struct {
+	// CheckpointEvery is the number of records after which Insert and Delete checkpoint the tree,
+	// bounding the length of the log. With 0 the tree is only checkpointed by calling Checkpoint.
+	CheckpointEvery int
+
+	// NoSync skips flushing the log to stable storage after each record, so that writes are faster
+	// but the changes since the last Sync, Checkpoint or Close may be lost in a crash.
+	NoSync bool
+
+	// Duplicates decides what Insert does with a value equal to one already in the tree.
+	Duplicates DuplicatePolicy
+}
+
+// DurableTree is a two-three tree whose changes survive a crash.
+// Every Insert and Delete is appended to a write-ahead log before it is applied, and a checkpoint
+// writes a snapshot of the whole tree so that the log can be emptied.
+// Opening the tree recovers it by replaying the log onto the last snapshot; a record cut short or damaged
+// by a crash, and everything logged after it, is discarded.
+// A DurableTree is not safe for concurrent use.
+type DurableTree[T any] struct {
+	tree  *TwoThreeTree[T]
+	codec Codec[T]
+	dir   string
+	opts  DurableOptions
+
+	log *os.File
+
+	// lsn is the sequence number of the last record logged.
+	lsn uint64
+
+	// offset is the end of the last complete record in the log.
+	offset int64
+
+	// logged is the number of records logged since the last checkpoint.
+	logged int
+
+	// frame is reused to encode each record.
+	frame []byte
+
+	closed bool
+}
+
+// OpenDurableTree opens the durable tree stored in the directory, creating the directory and an empty tree
+// if they do not exist. Values are ordered by the comparator and stored with the codec; both must match
+// those used when the tree was written.
+// It returns an error if the snapshot cannot be read, or a record that is intact cannot be replayed.
+func OpenDurableTree[T any](dir string, comparator func(a, b T) int, codec Codec[T], opts DurableOptions) (*DurableTree[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	durable := &DurableTree[T]{
+		tree:  NewTwoThreeTreeWithPolicy(comparator, opts.Duplicates),
+		codec: codec,
+		dir:   dir,
+		opts:  opts,
+	}
+	if err := durable.loadSnapshot(); err != nil {
+		return nil, err
+	}
+
+	log, err := os.OpenFile(filepath.Join(dir, walLogName), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	durable.log = log
+	if err := durable.replay(); err != nil {
+		log.Close()
+		return nil, err
+	}
+	return durable, nil
+}
+
+// loadSnapshot loads the tree from the snapshot, if there is one.
+func (durable *DurableTree[T]) loadSnapshot() error {
+	file, err := os.Open(filepath.Join(durable.dir, walSnapshotName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var lsn uint64
+	if err := binary.Read(file, binary.BigEndian, &lsn); err != nil {
+		return fmt.Errorf("reading snapshot: %w", unexpectedEOF(err))
+	}
+	values, err := decodeValues(file, durable.tree.comparator, durable.codec)
+	if err != nil {
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	durable.tree.root = buildSorted(values, durable.tree.comparator, durable.tree.duplicates)
+	durable.tree.size = len(values)
+	durable.lsn = lsn
+	return nil
+}
+
+// replay applies the records of the log that follow the snapshot, then truncates the log after the last
+// complete record so that new records are appended to an intact log.
+func (durable *DurableTree[T]) replay() error {
+	in := bufio.NewReader(durable.log)
+	for {
+		lsn, op, value, size, err := durable.readRecord(in)
+		if err == io.EOF || errors.Is(err, errTornRecord) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		durable.offset += int64(size)
+
+		// Records already in the snapshot remain if a crash came between writing it and emptying the log.
+		if lsn <= durable.lsn {
+			continue
+		}
+		durable.lsn = lsn
+		durable.logged++
+
+		switch op {
+		case walInsert:
+			// An insert rejected when it was logged is rejected again.
+			if err := durable.tree.Insert(value); err != nil && !errors.Is(err, ErrDuplicate) {
+				return err
+			}
+		case walDelete:
+			if _, err := durable.tree.Delete(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := durable.log.Truncate(durable.offset); err != nil {
+		return err
+	}
+	_, err := durable.log.Seek(durable.offset, io.SeekStart)
+	return err
+}
+
+// readRecord reads the next record of the log.
+// It returns the record and its size in the log, io.EOF at the end of the log,
+// or an error wrapping errTornRecord if the record is incomplete or fails its checksum.
+func (durable *DurableTree[T]) readRecord(in *bufio.Reader) (uint64, byte, T, int, error) {
+	var value T
+	var header [walFrameHeaderSize]byte
+	if n, err := io.ReadFull(in, header[:]); err != nil {
+		if n == 0 && err == io.EOF {
+			return 0, 0, value, 0, io.EOF
+		}
+		return 0, 0, value, 0, fmt.Errorf("%w: %v", errTornRecord, err)
+	}
+
+	length := binary.BigEndian.Uint32(header[0:])
+	if length < walBodyHeaderSize || length > maxElementLength+walBodyHeaderSize {
+		return 0, 0, value, 0, fmt.Errorf("%w: body is %d bytes long", errTornRecord, length)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(in, body); err != nil {
+		return 0, 0, value, 0, fmt.Errorf("%w: %v", errTornRecord, err)
+	}
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(header[4:]) {
+		return 0, 0, value, 0, fmt.Errorf("%w: %v", errTornRecord, ErrChecksum)
+	}
+
+	lsn, op := binary.BigEndian.Uint64(body), body[8]
+	if op != walInsert && op != walDelete {
+		return 0, 0, value, 0, fmt.Errorf("%w: record %d has unknown operation %d", ErrInvalidFormat, lsn, op)
+	}
+	value, err := durable.codec.Decode(body[walBodyHeaderSize:])
+	if err != nil {
+		return 0, 0, value, 0, err
+	}
+	return lsn, op, value, walFrameHeaderSize + int(length), nil
+}
+
+// append logs a change, and syncs the log unless NoSync is set.
+// If the record cannot be written, whatever part of it was written is cut from the log again.
+func (durable *DurableTree[T]) append(op byte, value T) error {
+	if durable.closed {
+		return ErrClosed
+	}
+
+	frame := append(durable.frame[:0], make([]byte, walFrameHeaderSize+walBodyHeaderSize)...)
+	binary.BigEndian.PutUint64(frame[walFrameHeaderSize:], durable.lsn+1)
+	frame[walFrameHeaderSize+8] = op
+	frame, err := durable.codec.Encode(frame, value)
+	if err != nil {
+		return err
+	}
+	durable.frame = frame
+
+	body := frame[walFrameHeaderSize:]
+	if len(body) > maxElementLength+walBodyHeaderSize {
+		return fmt.Errorf("%w: value is %d bytes long", ErrInvalidFormat, len(body)-walBodyHeaderSize)
+	}
+	binary.BigEndian.PutUint32(frame[0:], uint32(len(body)))
+	binary.BigEndian.PutUint32(frame[4:], crc32.ChecksumIEEE(body))
+
+	if _, err := durable.log.Write(frame); err != nil {
+		durable.rewind()
+		return err
+	}
+	if !durable.opts.NoSync {
+		if err := durable.log.Sync(); err != nil {
+			durable.rewind()
+			return err
+		}
+	}
+
+	durable.lsn++
+	durable.offset += int64(len(frame))
+	durable.logged++
+	return nil
+}
+
+// rewind cuts the log back to the end of the last complete record.
+func (durable *DurableTree[T]) rewind() {
+	if durable.log.Truncate(durable.offset) == nil {
+		durable.log.Seek(durable.offset, io.SeekStart)
+	}
+}
+
+// checkpointIfDue checkpoints the tree once CheckpointEvery records have been logged.
+func (durable *DurableTree[T]) checkpointIfDue() error {
+	if durable.opts.CheckpointEvery > 0 && durable.logged >= durable.opts.CheckpointEvery {
+		return durable.Checkpoint()
+	}
+	return nil
+}
+
+// Insert logs and inserts a value into the tree.
+// A value equal to one already in the tree is handled according to the tree's DuplicatePolicy;
+// a rejected value is not logged.
+func (durable *DurableTree[T]) Insert(value T) error {
+	if durable.opts.Duplicates == RejectDuplicates && durable.tree.Contains(value) {
+		return ErrDuplicate
+	}
+	if err := durable.append(walInsert, value); err != nil {
+		return err
+	}
+	if err := durable.tree.Insert(value); err != nil {
+		return err
+	}
+	return durable.checkpointIfDue()
+}
+
+// Delete logs and removes a value from the tree. A value that is not found is not logged.
+// It returns whether the value was found.
+func (durable *DurableTree[T]) Delete(value T) (bool, error) {
+	if !durable.tree.Contains(value) {
+		return false, nil
+	}
+	if err := durable.append(walDelete, value); err != nil {
+		return false, err
+	}
+	if _, err := durable.tree.Delete(value); err != nil {
+		return false, err
+	}
+	return true, durable.checkpointIfDue()
+}
+
+// Checkpoint writes a snapshot of the tree and empties the log.
+// The snapshot is written to a temporary file and renamed into place, so a crash leaves either
+// the old snapshot or the new one.
+func (durable *DurableTree[T]) Checkpoint() error {
+	if durable.closed {
+		return ErrClosed
+	}
+
+	path := filepath.Join(durable.dir, walSnapshotName)
+	if err := durable.writeSnapshot(path + ".tmp"); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+	if err := syncDir(durable.dir); err != nil {
+		return err
+	}
+
+	durable.offset, durable.logged = 0, 0
+	if err := durable.log.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := durable.log.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return durable.log.Sync()
+}
+
+// writeSnapshot writes the tree and the sequence number of the last record logged to a new file at path.
+func (durable *DurableTree[T]) writeSnapshot(path string) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if err := binary.Write(file, binary.BigEndian, durable.lsn); err != nil {
+		return err
+	}
+	if err := durable.tree.Encode(file, durable.codec); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// syncDir flushes the entries of a directory to stable storage, so that a rename within it survives a crash.
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return file.Sync()
+}
+
+// Sync flushes the log to stable storage. It is only needed when NoSync is set.
+func (durable *DurableTree[T]) Sync() error {
+	if durable.closed {
+		return ErrClosed
+	}
+	return durable.log.Sync()
+}
+
+// Close syncs and closes the log. The tree may not be used afterwards.
+// It does not checkpoint the tree, so the log is replayed when the tree is next opened.
+func (durable *DurableTree[T]) Close() error {
+	if durable.closed {
+		return ErrClosed
+	}
+	err := durable.log.Sync()
+	durable.closed = true
+	if closeErr := durable.log.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Tree returns the tree the log is applied to, for reading.
+// Changes made to it directly are not logged, and are lost when the tree is reopened.
+func (durable *DurableTree[T]) Tree() *TwoThreeTree[T] {
+	return durable.tree
+}
+
+// Get finds a value in the tree.
+// It returns the stored value equal to the given one, and whether it was found.
+func (durable *DurableTree[T]) Get(value T) (T, bool) {
+	return durable.tree.Get(value)
+}
+
+// Contains reports whether a value equal to the given one is stored in the tree.
+func (durable *DurableTree[T]) Contains(value T) bool {
+	return durable.tree.Contains(value)
+}
+
+// Len returns the number of values stored in the tree.
+func (durable *DurableTree[T]) Len() int {
+	return durable.tree.Len()
+}
@@ -0,0 +1,281 @@
+package trees
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// walStep is an operation applied to a durable tree and to the sorted slice it should match.
+type walStep struct {
+	insert bool
+	value  int
+}
+
+func openDurable(t *testing.T, dir string, opts DurableOptions) *DurableTree[int] {
+	t.Helper()
+	durable, err := OpenDurableTree[int](dir, intComparator, IntCodec{}, opts)
+	if err != nil {
+		t.Fatalf("OpenDurableTree() error = %v", err)
+	}
+	return durable
+}
+
+// applyStep applies the step to the tree and returns the values the tree should then hold.
+func applyStep(t *testing.T, durable *DurableTree[int], want []int, step walStep) []int {
+	t.Helper()
+	if step.insert {
+		if err := durable.Insert(step.value); err != nil {
+			t.Fatalf("Insert(%d) error = %v", step.value, err)
+		}
+		return insertSorted(want, step.value)
+	}
+	if _, err := durable.Delete(step.value); err != nil {
+		t.Fatalf("Delete(%d) error = %v", step.value, err)
+	}
+	return deleteSorted(want, step.value)
+}
+
+func insertSorted(values []int, value int) []int {
+	i := 0
+	for i < len(values) && values[i] <= value {
+		i++
+	}
+	return insertAt(append([]int(nil), values...), i, value)
+}
+
+func deleteSorted(values []int, value int) []int {
+	for i, v := range values {
+		if v == value {
+			return append(append([]int(nil), values[:i]...), values[i+1:]...)
+		}
+	}
+	return values
+}
+
+func randomSteps(seed int64, n int) []walStep {
+	rng := rand.New(rand.NewSource(seed))
+	steps := make([]walStep, n)
+	for i := range steps {
+		steps[i] = walStep{insert: rng.Intn(3) != 0, value: rng.Intn(20) - 10}
+	}
+	return steps
+}
+
+// copyDir copies the files of a durable tree's directory, truncating the log to cut bytes.
+func copyDir(t *testing.T, from, to string, cut int) {
+	t.Helper()
+	if err := os.MkdirAll(to, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{walSnapshotName, walLogName} {
+		data, err := os.ReadFile(filepath.Join(from, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name == walLogName {
+			data = data[:cut]
+		}
+		if err := os.WriteFile(filepath.Join(to, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDurableTreeReopens(t *testing.T) {
+	dir := t.TempDir()
+	durable := openDurable(t, dir, DurableOptions{})
+	var want []int
+	for _, step := range randomSteps(24, 200) {
+		want = applyStep(t, durable, want, step)
+	}
+	if err := durable.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	durable = openDurable(t, dir, DurableOptions{})
+	defer durable.Close()
+	if got := InOrder(durable.Tree().Root()); !reflect.DeepEqual(got, want) {
+		t.Errorf("after reopening, InOrder() = %v, want %v", got, want)
+	}
+	if err := durable.Tree().Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDurableTreeRecoversTruncatedLog(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// checkpointAfter is the number of steps after which the tree is checkpointed, or 0 for never.
+		checkpointAfter int
+	}{
+		{
+			name: "It replays every complete record of a log without a snapshot",
+		},
+		{
+			name:            "It replays every complete record onto the snapshot",
+			checkpointAfter: 15,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			durable := openDurable(t, dir, DurableOptions{NoSync: true})
+			steps := randomSteps(int64(tt.checkpointAfter), 40)
+
+			// states[i] holds the values once the log holds i records, and ends[i] the length of the log then.
+			var want []int
+			states, ends := [][]int{nil}, []int64{0}
+			for i, step := range steps {
+				if i == tt.checkpointAfter && i > 0 {
+					if err := durable.Checkpoint(); err != nil {
+						t.Fatalf("Checkpoint() error = %v", err)
+					}
+					states, ends = [][]int{want}, []int64{0}
+				}
+				want = applyStep(t, durable, want, step)
+				if durable.offset != ends[len(ends)-1] {
+					states, ends = append(states, want), append(ends, durable.offset)
+				}
+			}
+			if err := durable.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			record := 0
+			for cut := 0; cut <= int(ends[len(ends)-1]); cut++ {
+				for record+1 < len(ends) && ends[record+1] <= int64(cut) {
+					record++
+				}
+
+				crashed := filepath.Join(t.TempDir(), "crashed")
+				copyDir(t, dir, crashed, cut)
+				recovered := openDurable(t, crashed, DurableOptions{})
+				if got := InOrder(recovered.Tree().Root()); !reflect.DeepEqual(got, states[record]) {
+					t.Fatalf("log cut at byte %d recovered %v, want %v", cut, got, states[record])
+				}
+
+				// The torn record must be gone, or records appended after it would be lost.
+				if err := recovered.Insert(100); err != nil {
+					t.Fatal(err)
+				}
+				recovered.Close()
+				reopened := openDurable(t, crashed, DurableOptions{})
+				if got, want := InOrder(reopened.Tree().Root()), insertSorted(states[record], 100); !reflect.DeepEqual(got, want) {
+					t.Fatalf("log cut at byte %d and appended to recovered %v, want %v", cut, got, want)
+				}
+				reopened.Close()
+			}
+		})
+	}
+}
+
+func TestDurableTreeDiscardsDamagedRecords(t *testing.T) {
+	dir := t.TempDir()
+	durable := openDurable(t, dir, DurableOptions{})
+	var want []int
+	var secondEnd int64
+	for i := 0; i < 5; i++ {
+		want = applyStep(t, durable, want, walStep{insert: true, value: i})
+		if i == 1 {
+			secondEnd = durable.offset
+		}
+	}
+	durable.Close()
+
+	// Flip a byte in the value of the third record.
+	path := filepath.Join(dir, walLogName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[secondEnd+walFrameHeaderSize+walBodyHeaderSize] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	durable = openDurable(t, dir, DurableOptions{})
+	defer durable.Close()
+	if got := InOrder(durable.Tree().Root()); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("InOrder() = %v, want the records before the damaged one, %v", got, want[:2])
+	}
+}
+
+func TestDurableTreeCheckpoints(t *testing.T) {
+	dir := t.TempDir()
+	durable := openDurable(t, dir, DurableOptions{CheckpointEvery: 10})
+	var want []int
+	for _, step := range randomSteps(5, 95) {
+		want = applyStep(t, durable, want, step)
+	}
+	if durable.logged >= 10 {
+		t.Errorf("%d record(s) logged since the last checkpoint, want fewer than 10", durable.logged)
+	}
+
+	// A crash between writing the snapshot and emptying the log leaves records the snapshot already holds.
+	stale, err := os.ReadFile(filepath.Join(dir, walLogName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := durable.Checkpoint(); err != nil {
+		t.Fatalf("Checkpoint() error = %v", err)
+	}
+	durable.Close()
+	if err := os.WriteFile(filepath.Join(dir, walLogName), stale, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	durable = openDurable(t, dir, DurableOptions{CheckpointEvery: 10})
+	defer durable.Close()
+	if got := InOrder(durable.Tree().Root()); !reflect.DeepEqual(got, want) {
+		t.Errorf("InOrder() = %v, want %v", got, want)
+	}
+}
+
+func TestDurableTreePolicies(t *testing.T) {
+	dir := t.TempDir()
+	opts := DurableOptions{Duplicates: RejectDuplicates}
+	durable := openDurable(t, dir, opts)
+	if err := durable.Insert(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := durable.Insert(1); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Insert() error = %v, want %v", err, ErrDuplicate)
+	}
+	if found, err := durable.Delete(2); found || err != nil {
+		t.Errorf("Delete(2) = %v, %v, want false, nil", found, err)
+	}
+	if durable.lsn != 1 {
+		t.Errorf("%d record(s) logged, want only the accepted insert", durable.lsn)
+	}
+	if err := durable.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := durable.Insert(2); !errors.Is(err, ErrClosed) {
+		t.Errorf("Insert() after Close() error = %v, want %v", err, ErrClosed)
+	}
+
+	durable = openDurable(t, dir, opts)
+	defer durable.Close()
+	if durable.Len() != 1 || !durable.Contains(1) {
+		t.Errorf("after reopening, InOrder() = %v, want [1]", InOrder(durable.Tree().Root()))
+	}
+}
+
+func TestDurableTreeRejectsCorruptSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, walSnapshotName), []byte("01234567 but this is not a snapshot of a tree"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenDurableTree[int](dir, intComparator, IntCodec{}, DurableOptions{}); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("OpenDurableTree() error = %v, want %v", err, ErrInvalidFormat)
+	}
+}
@@ -26,13 +26,39 @@ type TwoThreeNode[T any] struct {
 	// returns -1 if a < b, 0 if a == b, 1 if a > b
 	comparator func(T, T) int
 
+	// duplicates decides what Insert does with a value equal to one already in the tree.
+	duplicates DuplicatePolicy
+
 	// the height of the tree.
 	height int
+
+	// the number of values in the subtree rooted at this node.
+	size int
 }
 
+// DuplicatePolicy decides what Insert does with a value that compares equal to one already in the tree.
+type DuplicatePolicy int
+
+const (
+	// AllowDuplicates keeps every inserted value, so the tree is a multiset.
+	// Equal values are kept in the order they were inserted.
+	AllowDuplicates DuplicatePolicy = iota
+	// RejectDuplicates leaves the tree unchanged and returns ErrDuplicate.
+	RejectDuplicates
+	// ReplaceDuplicates overwrites the value already in the tree.
+	ReplaceDuplicates
+)
+
+// ErrDuplicate is returned by Insert when the tree rejects duplicates and already holds an equal value.
+var ErrDuplicate = errors.New("value already exists in the tree")
+
 // TwoThreeNodeInt is a constructor for a two-three tree with int values.
 // It returns a root node of a two-three tree.
 func TwoThreeNodeInt(init *int) *TwoThreeNode[int] {
+	size := 0
+	if init != nil {
+		size = 1
+	}
 	return &TwoThreeNode[int]{
 		firstData:   init,
 		secondData:  nil,
@@ -41,11 +67,20 @@ func TwoThreeNodeInt(init *int) *TwoThreeNode[int] {
 		thirdChild:  nil,
 		parent:      nil,
 		comparator:  intComparator,
-		height:      0,
+		height:      1,
+		size:        size,
 	}
 }
 
+// New is a constructor for a two-three tree that allows duplicate values.
+// It returns a root node of a two-three tree.
 func New[T any](value T, comparator func(a, b T) int) *TwoThreeNode[T] {
+	return NewWithPolicy(value, comparator, AllowDuplicates)
+}
+
+// NewWithPolicy is a constructor for a two-three tree that handles duplicate values according to the given policy.
+// It returns a root node of a two-three tree.
+func NewWithPolicy[T any](value T, comparator func(a, b T) int, duplicates DuplicatePolicy) *TwoThreeNode[T] {
 	return &TwoThreeNode[T]{
 		firstData:   &value,
 		secondData:  nil,
@@ -54,7 +89,9 @@ func New[T any](value T, comparator func(a, b T) int) *TwoThreeNode[T] {
 		thirdChild:  nil,
 		parent:      nil,
 		comparator:  comparator,
-		height:      0,
+		duplicates:  duplicates,
+		height:      1,
+		size:        1,
 	}
 }
 
@@ -153,118 +190,153 @@ func datumCount[T any](node *TwoThreeNode[T]) int {
 	return count
 }
 
+// nodeData returns the data values of the given twoThreeNode, in order.
+func nodeData[T any](node *TwoThreeNode[T]) []*T {
+	var data []*T
+	for _, datum := range []*T{node.firstData, node.secondData} {
+		if datum != nil {
+			data = append(data, datum)
+		}
+	}
+	return data
+}
+
+// setNodeData assigns up to two data values to the given twoThreeNode, in order.
+// Missing values are set to nil.
+func setNodeData[T any](node *TwoThreeNode[T], data []*T) {
+	node.firstData, node.secondData = nil, nil
+	if len(data) > 0 {
+		node.firstData = data[0]
+	}
+	if len(data) > 1 {
+		node.secondData = data[1]
+	}
+}
+
+// nodeChildren returns the children of the given twoThreeNode, in order.
+func nodeChildren[T any](node *TwoThreeNode[T]) []*TwoThreeNode[T] {
+	var children []*TwoThreeNode[T]
+	for _, child := range []*TwoThreeNode[T]{node.firstChild, node.secondChild, node.thirdChild} {
+		if child != nil {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// setNodeChildren assigns up to three children to the given twoThreeNode, in order.
+// The parent pointer of every assigned child is set to node. Missing children are set to nil.
+func setNodeChildren[T any](node *TwoThreeNode[T], children []*TwoThreeNode[T]) {
+	slots := []**TwoThreeNode[T]{&node.firstChild, &node.secondChild, &node.thirdChild}
+	for i, slot := range slots {
+		*slot = nil
+		if i < len(children) {
+			*slot = children[i]
+			children[i].parent = node
+		}
+	}
+}
+
 // sortData returns the data of a node and a given value, sorted in ascending order.
+// The value is placed after any data equal to it, so duplicates keep their insertion order.
 // It returns the sorted data
 func sortData[T any](node *TwoThreeNode[T], value T) (*T, *T, *T) {
-	if node.comparator(value, *node.firstData) <= 0 {
+	if node.comparator(value, *node.firstData) < 0 {
 		return &value, node.firstData, node.secondData
 	}
-	if node.comparator(value, *node.secondData) <= 0 {
+	if node.comparator(value, *node.secondData) < 0 {
 		return node.firstData, &value, node.secondData
 	}
 	return node.firstData, node.secondData, &value
 }
 
-func findRoot[T any](node *TwoThreeNode[T]) *TwoThreeNode[T] {
-	if node.parent == nil {
-		return node
-	}
-	node.height = 1 + maxHeight(node.firstChild, node.secondChild, node.thirdChild)
-	return findRoot(node.parent)
+// twoThreeLayout is the nodeLayout of TwoThreeNode. The two-three tree is balanced as a B-tree of order 3
+// through it, keeping the parent pointers, heights and sizes of its nodes up to date on the way.
+type twoThreeLayout[T any] struct {
+	// onUpdate, if not nil, is called on every node whose data or children changed, after its height and size.
+	onUpdate func(node *TwoThreeNode[T])
 }
 
-// rebalance rebalances the tree after a node has been inserted.
-// It recurses up the tree until it finds a node that is not full, or the root node.
-// It returns the new root of the tree.
-func rebalance[T any](node *TwoThreeNode[T], value T, tmpChildNode *TwoThreeNode[T]) *TwoThreeNode[T] {
-	finalSplit := node.parent == nil || datumCount(node.parent) == 1
+func (twoThreeLayout[T]) keys(node *TwoThreeNode[T]) []*T {
+	return nodeData(node)
+}
 
-	if datumCount(node) == 1 {
-		insertIntoSingleDatumNode(node, value)
-		return findRoot(node)
-	}
+func (twoThreeLayout[T]) setKeys(node *TwoThreeNode[T], data []*T) {
+	setNodeData(node, data)
+}
 
-	// node cannot be split, so recurse up the tree
-	min, mid, max := sortData(node, value)
-	parent := node.parent
-
-	// We can split this node.
-	// it:
-	// - has two data values
-	// - is the root node, or
-	// - its parent has only one data value
-	node.firstData = min
-	node.secondData = nil
-
-	otherNode := TwoThreeNode[T]{
-		firstData:   max,
-		secondData:  nil,
-		firstChild:  nil,
-		secondChild: nil,
-		thirdChild:  nil,
-		parent:      parent,
-		comparator:  node.comparator,
-	}
+func (twoThreeLayout[T]) children(node *TwoThreeNode[T]) []*TwoThreeNode[T] {
+	return nodeChildren(node)
+}
 
-	leftChildren, rightChildren := partitionChildNodes(*mid, []*TwoThreeNode[T]{node.firstChild, node.secondChild, node.thirdChild, tmpChildNode})
+func (twoThreeLayout[T]) setChildren(node *TwoThreeNode[T], children []*TwoThreeNode[T]) {
+	setNodeChildren(node, children)
+}
 
-	node.firstChild = nil
-	node.secondChild = nil
-	node.thirdChild = nil
+func (twoThreeLayout[T]) newNode(like *TwoThreeNode[T]) *TwoThreeNode[T] {
+	return &TwoThreeNode[T]{
+		comparator: like.comparator,
+		duplicates: like.duplicates,
+	}
+}
 
-	for i, child := range leftChildren {
-		child.parent = node
-		if i == 0 {
-			node.firstChild = child
-		} else {
-			node.secondChild = child
-		}
+func (layout twoThreeLayout[T]) update(node *TwoThreeNode[T]) {
+	node.height = 1 + maxHeight(node.firstChild, node.secondChild, node.thirdChild)
+	resize(node)
+	if layout.onUpdate != nil {
+		layout.onUpdate(node)
 	}
+}
 
-	for i, child := range rightChildren {
-		child.parent = &otherNode
-		if i == 0 {
-			otherNode.firstChild = child
-		} else {
-			otherNode.thirdChild = child
+// pathTo returns the nodes from the root of the tree down to node, found by following its parent pointers.
+func pathTo[T any](node *TwoThreeNode[T]) []pathStep[*TwoThreeNode[T]] {
+	var path []pathStep[*TwoThreeNode[T]]
+	for ; node != nil; node = node.parent {
+		index := 0
+		if node.parent != nil {
+			index = indexOfChild(node.parent, node)
 		}
+		path = append(path, pathStep[*TwoThreeNode[T]]{node: node, index: index})
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
 	}
+	return path
+}
 
-	node.height = 1 + maxHeight(node.firstChild, node.secondChild, node.thirdChild)
-	otherNode.height = 1 + maxHeight(otherNode.firstChild, otherNode.secondChild, otherNode.thirdChild)
-
-	if parent == nil {
-		parent := TwoThreeNode[T]{
-			firstData:   mid,
-			secondData:  nil,
-			firstChild:  node,
-			secondChild: &otherNode,
-			thirdChild:  nil,
-			parent:      nil,
-			comparator:  node.comparator,
-			height:      1 + maxHeight(node, &otherNode),
-		}
-		node.parent = &parent
-		otherNode.parent = &parent
-		return &parent
-	}
-
-	if finalSplit {
-		if node.parent.firstChild == node {
-			// node is the firstChild of its parent
-			// - set otherNode as the secondChild of the parent and
-			// - set secondChild of node as the thirdChild of the parent
-			parent.thirdChild = parent.secondChild
-			parent.secondChild = &otherNode
-		} else {
-			// node is the secondChild of its parent
-			parent.thirdChild = &otherNode
-			otherNode.parent = parent
-		}
-		return rebalance(parent, *mid, nil)
-	} else {
-		return rebalance(parent, *mid, &otherNode)
+// rebalance rebalances the tree after a value has been inserted into the leaf node.
+// It returns the new root of the tree.
+func rebalance[T any](layout twoThreeLayout[T], node *TwoThreeNode[T], value T) *TwoThreeNode[T] {
+	if datumCount(node) == 1 {
+		insertIntoSingleDatumNode(node, value)
+		return layout.settle(node, nodeData(node), nil)
 	}
+
+	min, mid, max := sortData(node, value)
+	return layout.settle(node, []*T{min, mid, max}, nil)
+}
+
+// insertAt returns the slice with elem inserted at index i.
+func insertAt[E any](slice []E, i int, elem E) []E {
+	slice = append(slice, elem)
+	copy(slice[i+1:], slice[i:])
+	slice[i] = elem
+	return slice
+}
+
+// settle stores the given data and children in node, which may be one value too many for a 2- or 3-node.
+// The tree is balanced as a B-tree of order 3: a node with three data values is split around the middle one,
+// which is pushed up into the parent together with the new right half of node, and so on up the tree.
+// Values and children are placed by position rather than by comparison, so that separators equal
+// to a value keep their order. The height and size of every node on the way to the root are updated.
+// It returns the new root of the tree.
+func settle[T any](node *TwoThreeNode[T], data []*T, children []*TwoThreeNode[T]) *TwoThreeNode[T] {
+	return twoThreeLayout[T]{}.settle(node, data, children)
+}
+
+// settle does what the package-level settle does, calling the layout's onUpdate on every node it changes.
+func (layout twoThreeLayout[T]) settle(node *TwoThreeNode[T], data []*T, children []*TwoThreeNode[T]) *TwoThreeNode[T] {
+	return settlePath[*T, *TwoThreeNode[T]](layout, 3, pathTo(node), data, children)
 }
 
 func maxHeight[T any](nodes ...*TwoThreeNode[T]) int {
@@ -283,6 +355,16 @@ func maxHeight[T any](nodes ...*TwoThreeNode[T]) int {
 	return max
 }
 
+// resize recalculates the number of values in the subtree rooted at node from its data and children.
+func resize[T any](node *TwoThreeNode[T]) {
+	node.size = datumCount(node)
+	for _, child := range []*TwoThreeNode[T]{node.firstChild, node.secondChild, node.thirdChild} {
+		if child != nil {
+			node.size += child.size
+		}
+	}
+}
+
 // insertIntoSingleDatumNode inserts a value into a single-datum node.
 // It returns node after inserting the value.
 func insertIntoSingleDatumNode[T any](node *TwoThreeNode[T], value T) *TwoThreeNode[T] {
@@ -293,52 +375,62 @@ func insertIntoSingleDatumNode[T any](node *TwoThreeNode[T], value T) *TwoThreeN
 		node.secondData = &value
 	}
 	node.height = maxHeight(node.firstChild, node.secondChild) + 1
+	resize(node)
 	return node
 }
 
-// partitionChildeNodes partitions the child nodes of a node into two groups based on the mid value given.
-// It returns the two groups.
-func partitionChildNodes[T any](midValue T, childNodes []*TwoThreeNode[T]) ([]*TwoThreeNode[T], []*TwoThreeNode[T]) {
-	var leftChildNodes []*TwoThreeNode[T]
-	var rightChildNodes []*TwoThreeNode[T]
+// Insert inserts a value into the tree.
+// A value equal to one already in the tree is handled according to the tree's DuplicatePolicy.
+// Note that the root of the tree may be modified by this operation.
+// It returns the root node of the tree.
+func Insert[T any](root *TwoThreeNode[T], value T) (*TwoThreeNode[T], error) {
+	root, _, err := insert(twoThreeLayout[T]{}, root, value)
+	return root, err
+}
 
-	appendOrPrepend := func(childNode *TwoThreeNode[T], childNodes []*TwoThreeNode[T]) []*TwoThreeNode[T] {
-		if len(childNodes) == 0 || childNodes[0].comparator(*childNodes[0].firstData, *childNode.firstData) < 0 {
-			childNodes = append(childNodes, childNode)
-		} else {
-			childNodes = append([]*TwoThreeNode[T]{childNode}, childNodes...)
-		}
+// insert inserts a value into the tree through the given layout, honouring the tree's DuplicatePolicy.
+// It returns the root node of the tree, and whether an existing value was replaced rather than a new one added.
+func insert[T any](layout twoThreeLayout[T], root *TwoThreeNode[T], value T) (*TwoThreeNode[T], bool, error) {
+	if root == nil {
+		return nil, false, errors.New("cannot insert into a nil node")
+	}
 
-		return childNodes
+	// A root without data (e.g. TwoThreeNodeInt(nil)) is an empty tree.
+	if datumCount(root) == 0 && isLeaf(*root) {
+		root.firstData = &value
+		layout.update(root)
+		return root, false, nil
 	}
 
-	for _, childNode := range childNodes {
-		if childNode == nil {
-			continue
+	if root.duplicates != AllowDuplicates {
+		existing, idx, err := findNode(root, value)
+		if err != nil {
+			return nil, false, err
 		}
-		if childNode.comparator(*childNode.firstData, midValue) < 0 {
-			leftChildNodes = appendOrPrepend(childNode, leftChildNodes)
-		} else {
-			rightChildNodes = appendOrPrepend(childNode, rightChildNodes)
+		if existing != nil {
+			if root.duplicates == RejectDuplicates {
+				return root, false, ErrDuplicate
+			}
+			data := nodeData(existing)
+			*data[idx] = value
+			if layout.onUpdate != nil {
+				// The replaced value may differ in what is recorded about it, so its node and every
+				// node above it are updated, without changing the shape of the tree.
+				root = layout.settle(existing, data, nodeChildren(existing))
+			}
+			return root, true, nil
 		}
 	}
 
-	return leftChildNodes, rightChildNodes
-}
-
-// Insert inserts a value into the tree.
-// Note that the root of the tree may be modified by this operation.
-// It returns the root node of the tree.
-func Insert[T any](root *TwoThreeNode[T], value T) (*TwoThreeNode[T], error) {
 	node, err := findLeaf(root, value)
 	if err != nil {
 		goto EXIT_ERROR
 	}
 
-	return rebalance(node, value, nil), nil
+	return rebalance(layout, node, value), false, nil
 
 EXIT_ERROR:
-	return nil, err
+	return nil, false, err
 }
 
 type queueElement[T any] struct {
@@ -412,9 +504,14 @@ func Print[T any](node *TwoThreeNode[T]) string {
 	return str
 }
 
+// BFS traverses the tree in breadth-first order.
+// It returns the values of the tree, level by level.
 func BFS[T any](root *TwoThreeNode[T]) []T {
-	queue := []*TwoThreeNode[T]{root}
 	var result []T
+	if root == nil || root.firstData == nil {
+		return result
+	}
+	queue := []*TwoThreeNode[T]{root}
 	for len(queue) > 0 {
 		node := queue[0]
 		queue = queue[1:]
@@ -436,7 +533,12 @@ func BFS[T any](root *TwoThreeNode[T]) []T {
 	return result
 }
 
+// Get finds a value in the tree.
+// It returns a pointer to the stored value, or nil if the value is not in the tree.
 func Get[T any](node *TwoThreeNode[T], value T) *T {
+	if node == nil {
+		return nil
+	}
 	if node.firstData != nil && node.comparator(value, *node.firstData) == 0 {
 		return node.firstData
 	}
@@ -424,7 +424,7 @@ func Test_sortData(t *testing.T) {
 			want2: 2,
 		},
 		{
-			name: "Returns [value, firstData, secondData] when value equal to firstData",
+			name: "Returns [firstData, value, secondData] when value equal to firstData",
 			args: args{
 				node:  ttni().setFD(1).setSD(2),
 				value: 1,
@@ -434,7 +434,7 @@ func Test_sortData(t *testing.T) {
 			want2: 2,
 		},
 		{
-			name: "Returns [firstData, value, secondData] when value equal to secondData",
+			name: "Returns [firstData, secondData, value] when value equal to secondData",
 			args: args{
 				node:  ttni().setFD(1).setSD(2),
 				value: 2,
@@ -579,44 +579,6 @@ func TestInsertMultiple(t *testing.T) {
 	})
 }
 
-func Test_partitionChildNodes(t *testing.T) {
-	type args struct {
-		midValue   int
-		childNodes []*TwoThreeNode[int]
-	}
-	tests := []struct {
-		name  string
-		args  args
-		want  []*TwoThreeNode[int]
-		want1 []*TwoThreeNode[int]
-	}{
-		{
-			name: "It partitions the child nodes correctly",
-			args: args{
-				midValue:   5,
-				childNodes: []*TwoThreeNode[int]{ttni().setFD(11), ttni().setFD(2), ttni().setFD(7), ttni().setFD(3)},
-			},
-			want:  []*TwoThreeNode[int]{ttni().setFD(2), ttni().setFD(3)},
-			want1: []*TwoThreeNode[int]{ttni().setFD(7), ttni().setFD(11)},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, got1 := partitionChildNodes(tt.args.midValue, tt.args.childNodes)
-			for i, node := range got {
-				if !reflect.DeepEqual(*node.firstData, *tt.want[i].firstData) {
-					t.Errorf("partitionChildNodes() got = %d, want %d", *node.firstData, *tt.want[i].firstData)
-				}
-			}
-			for i, node := range got1 {
-				if !reflect.DeepEqual(*node.firstData, *tt.want1[i].firstData) {
-					t.Errorf("partitionChildNodes() got1 = %d, want %d", *node.firstData, *tt.want1[i].firstData)
-				}
-			}
-		})
-	}
-}
-
 func Test_maxHeight(t *testing.T) {
 	type args struct {
 		nodes []*TwoThreeNode[int]